@@ -18,9 +18,12 @@ func (opts *GetOpt) BoolValueV(flags []rune, longFlags []string, required bool,
 	def.argConv = func(arg string) error {
 		var err error
 		result, err = strconv.ParseBool(arg)
-		def.count++
 		return err
 	}
+	def.argNegate = func() error {
+		result = false
+		return nil
+	}
 	def.argReset = func() {
 		result = false
 	}
@@ -37,14 +40,48 @@ func (opts *GetOpt) BoolDefaultV(flags []rune, longFlags []string, value bool, h
 		posixOpts: flags,
 		longOpts:  longFlags,
 		help:      help,
+		defValue:  strconv.FormatBool(value),
 		argType:   "bool",
 	}
 	def.argConv = func(arg string) error {
 		var err error
 		result, err = strconv.ParseBool(arg)
-		def.count++
 		return err
 	}
+	def.argNegate = func() error {
+		result = false
+		return nil
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.safeAdd(def)
+}
+
+func (opts *GetOpt) BoolOptional(flag rune, longFlag string, value bool, implicit bool, help string) (*bool, error) {
+	return opts.BoolOptionalV([]rune{flag}, []string{longFlag}, value, implicit, help)
+}
+
+func (opts *GetOpt) BoolOptionalV(flags []rune, longFlags []string, value bool, implicit bool, help string) (*bool, error) {
+	result := value
+	def := optDef{
+		posixOpts:   flags,
+		longOpts:    longFlags,
+		help:        help,
+		defValue:    strconv.FormatBool(value),
+		optionalArg: true,
+		implicit:    strconv.FormatBool(implicit),
+		argType:     "bool",
+	}
+	def.argConv = func(arg string) error {
+		var err error
+		result, err = strconv.ParseBool(arg)
+		return err
+	}
+	def.argNegate = func() error {
+		result = false
+		return nil
+	}
 	def.argReset = func() {
 		result = value
 	}
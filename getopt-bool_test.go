@@ -125,6 +125,61 @@ func TestGetOpt_Bool(t *testing.T) {
 			wantValue:   false,
 			wantParseOk: true,
 		},
+		{
+			name: "optional argument absent",
+			init: func(getopt *GetOpt) (*bool, error) {
+				return getopt.BoolOptional('f', "--bool", false, true, "help")
+			},
+			args:        []string{"prog"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   false,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument implicit",
+			init: func(getopt *GetOpt) (*bool, error) {
+				return getopt.BoolOptional('f', "--bool", false, true, "help")
+			},
+			args:        []string{"prog", "-f"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   true,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument inline",
+			init: func(getopt *GetOpt) (*bool, error) {
+				return getopt.BoolOptional('f', "--bool", false, true, "help")
+			},
+			args:        []string{"prog", "-ffalse"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   false,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument longopt inline",
+			init: func(getopt *GetOpt) (*bool, error) {
+				return getopt.BoolOptional('f', "--bool", false, true, "help")
+			},
+			args:        []string{"prog", "--bool=false"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   false,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument not taken from next word",
+			init: func(getopt *GetOpt) (*bool, error) {
+				return getopt.BoolOptional('f', "--bool", false, true, "help")
+			},
+			args:        []string{"prog", "--bool", "false"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   true,
+			wantParseOk: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
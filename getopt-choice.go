@@ -0,0 +1,98 @@
+package getopt
+
+import (
+	"errors"
+	"strings"
+)
+
+func (opts *GetOpt) Choice(flag rune, longFlag string, choices []string, value string, help string) (*string, error) {
+	return opts.ChoiceV([]rune{flag}, []string{longFlag}, choices, value, help)
+}
+
+func (opts *GetOpt) ChoiceV(flags []rune, longFlags []string, choices []string, value string, help string) (*string, error) {
+	result := value
+	def := optDef{
+		posixOpts: flags,
+		longOpts:  longFlags,
+		help:      help,
+		choices:   choices,
+		defValue:  value,
+		argType:   strings.Join(choices, "|"),
+	}
+	def.argConv = func(arg string) error {
+		choice, err := matchChoice(choices, arg)
+		if err == nil {
+			result = choice
+		}
+		return err
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.safeAdd(def)
+}
+
+func (opts *GetOpt) ChoiceList(flag rune, longFlag string, choices []string, help string) (*[]string, error) {
+	return opts.ChoiceListV([]rune{flag}, []string{longFlag}, choices, help)
+}
+
+func (opts *GetOpt) ChoiceListV(flags []rune, longFlags []string, choices []string, help string) (*[]string, error) {
+	result := make([]string, 0)
+	def := optDef{
+		posixOpts: flags,
+		longOpts:  longFlags,
+		help:      help,
+		multiple:  true,
+		list:      true,
+		choices:   choices,
+		argType:   strings.Join(choices, "|"),
+	}
+	def.argConv = func(arg string) error {
+		choice, err := matchChoice(choices, arg)
+		if err == nil {
+			result = append(result, choice)
+		}
+		return err
+	}
+	def.argReset = func() {
+		result = make([]string, 0)
+	}
+	return &result, opts.safeAdd(def)
+}
+
+func matchChoice(choices []string, arg string) (string, error) {
+	for _, choice := range choices {
+		if choice == arg {
+			return choice, nil
+		}
+	}
+	for _, choice := range choices {
+		if strings.EqualFold(choice, arg) {
+			return choice, nil
+		}
+	}
+	candidates := make([]string, 0)
+	if prefix := strings.ToLower(arg); prefix != "" {
+		for _, choice := range choices {
+			if lower := strings.ToLower(choice); len(lower) > len(prefix) && strings.HasPrefix(lower, prefix) {
+				candidates = append(candidates, choice)
+			}
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	} else if len(candidates) > 1 {
+		return "", errors.New("Ambiguous value `" + arg + "`, could be " + strings.Join(candidates, "/"))
+	}
+	return "", errors.New("Invalid value `" + arg + "`, expected one of " + strings.Join(choices, ", "))
+}
+
+func choiceCallback(choices []string, callback func(string) error) func(string) error {
+	return func(arg string) error {
+		choice, err := matchChoice(choices, arg)
+		if err != nil {
+			return err
+		}
+		return callback(choice)
+	}
+}
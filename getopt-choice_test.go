@@ -0,0 +1,98 @@
+package getopt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetOpt_Choice(t *testing.T) {
+	type test struct {
+		name        string
+		args        []string
+		wantValue   string
+		wantParseOk bool
+	}
+	tests := []test{
+		{"default", []string{"prog"}, "text", true},
+		{"exact", []string{"prog", "--format=yaml"}, "yaml", true},
+		{"case insensitive", []string{"prog", "-f", "JSON"}, "json", true},
+		{"unique prefix", []string{"prog", "--format=y"}, "yaml", true},
+		{"exact wins over prefix", []string{"prog", "--format=json"}, "json", true},
+		{"ambiguous prefix", []string{"prog", "--format=j"}, "", false},
+		{"invalid", []string{"prog", "--format=xml"}, "", false},
+		{"empty", []string{"prog", "--format="}, "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+				return true, err
+			})
+			result, err := opts.Choice('f', "--format", []string{"json", "jsonl", "yaml", "text"}, "text", "output format")
+			if err != nil {
+				t.Fatalf("Unexpected error %v on setup", err)
+			}
+			if _, err := opts.Parse(test.args, false); (err == nil) != test.wantParseOk {
+				t.Errorf("Parse() error = %v, want ok = %v", err, test.wantParseOk)
+			} else if err == nil && *result != test.wantValue {
+				t.Errorf("Unexpected value: %v (expected: %v)", *result, test.wantValue)
+			}
+		})
+	}
+}
+
+func TestMatchChoice(t *testing.T) {
+	tests := []struct {
+		choices []string
+		arg     string
+		want    string
+		wantOk  bool
+	}{
+		{[]string{"über", "übel"}, "\xc3", "", false},
+		{[]string{"über", "Ärger"}, "är", "Ärger", true},
+		{[]string{"\u212aelvin", "kilo"}, "ke", "\u212aelvin", true},
+		{[]string{"\u212aelvin", "kilo"}, "k", "", false},
+	}
+	for _, test := range tests {
+		if got, err := matchChoice(test.choices, test.arg); got != test.want || (err == nil) != test.wantOk {
+			t.Errorf("matchChoice(%q, %q) = %q, %v", test.choices, test.arg, got, err)
+		}
+	}
+}
+
+func TestGetOpt_ChoiceList(t *testing.T) {
+	opts := New()
+	result, _ := opts.ChoiceList('l', "--level", []string{"debug", "info", "warn"}, "levels")
+	if _, err := opts.Parse([]string{"prog", "-lD", "--level=info", "-l", "WARN"}, false); err != nil {
+		t.Fatalf("Parse() unexpected error %v", err)
+	}
+	if want := []string{"debug", "info", "warn"}; !reflect.DeepEqual(*result, want) {
+		t.Errorf("Unexpected value: %v (expected: %v)", *result, want)
+	}
+}
+
+func TestGetOpt_MarshalChoice(t *testing.T) {
+	type target struct {
+		Format string   `flag:"f,format" choices:"json,yaml,text" default:"text"`
+		Levels []string `flag:"l,level" choices:"debug,info,warn"`
+	}
+	tests := []struct {
+		args        []string
+		want        target
+		wantParseOk bool
+	}{
+		{[]string{"prog"}, target{Format: "text"}, true},
+		{[]string{"prog", "-fY", "-ld", "-li"}, target{Format: "yaml", Levels: []string{"debug", "info"}}, true},
+		{[]string{"prog", "-f", "xml"}, target{}, false},
+	}
+	for _, test := range tests {
+		result := target{}
+		opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+			return true, err
+		})
+		if _, err := opts.Marshal(&result, test.args, false); (err == nil) != test.wantParseOk {
+			t.Errorf("Marshal(%v) error = %v, want ok = %v", test.args, err, test.wantParseOk)
+		} else if err == nil && !reflect.DeepEqual(result, test.want) {
+			t.Errorf("Marshal(%v) = %v, want %v", test.args, result, test.want)
+		}
+	}
+}
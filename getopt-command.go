@@ -0,0 +1,84 @@
+package getopt
+
+import (
+	"errors"
+	"iter"
+)
+
+func (opts *GetOpt) Command(name string, help string, handler func(cmd *GetOpt, args []string) error) *GetOpt {
+	cmd := &GetOpt{
+		commandHelp: help,
+		description: make([]string, 0),
+		handler:     handler,
+		name:        name,
+		optionMap:   make(map[string]*optDef),
+		optionList:  make([]*optDef, 0),
+		parent:      opts,
+		version:     opts.version,
+	}
+	if _, found := opts.lookup("--help"); found {
+		_ = cmd.FlagFunc('h', "--help", func() error { return cmd.Help() }, "Print help")
+	}
+	opts.commands = append(opts.commands, cmd)
+	return cmd
+}
+
+func (opts *GetOpt) SelectedCommand() *GetOpt {
+	return opts.command
+}
+
+func (opts *GetOpt) commandSeq(args []string, opt Option, posix bool) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for _, cmd := range opts.commands {
+			if cmd.name == *opt.Arg {
+				opts.command = cmd
+				if cmd.onSelect != nil {
+					cmd.onSelect()
+				}
+				for arg, err := range cmd.parseSeq(args, opt.Index, posix) {
+					if !yield(arg, err) {
+						return
+					}
+				}
+				return
+			}
+		}
+		var err error
+		unknown := &PositionError{Err: errors.New("Unknown command `" + *opt.Arg + "`"), Args: args, Option: opt}
+		if opts.done, err = opts.handleError(unknown, opt); err != nil {
+			yield("", err)
+		}
+	}
+}
+
+func (opts *GetOpt) dispatch(args []string) error {
+	cmd := opts
+	for cmd.command != nil {
+		cmd = cmd.command
+	}
+	if cmd != opts && cmd.handler != nil {
+		return cmd.handler(cmd, args)
+	}
+	return nil
+}
+
+func (opts *GetOpt) fullName() string {
+	if opts.parent == nil {
+		return opts.name
+	}
+	return opts.parent.fullName() + " " + opts.name
+}
+
+func (opts *GetOpt) shadows(opt *optDef) bool {
+	for _, posixOpt := range opt.posixOpts {
+		if _, found := opts.optionMap["-"+string(posixOpt)]; found {
+			return true
+		}
+	}
+	for _, longOpt := range opt.longOpts {
+		if _, found := opts.optionMap[longOpt]; found {
+			return true
+		}
+	}
+	return false
+}
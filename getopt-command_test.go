@@ -0,0 +1,131 @@
+package getopt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetOpt_Command(t *testing.T) {
+	type test struct {
+		name        string
+		args        []string
+		wantParseOk bool
+		wantDone    bool
+		wantCommand string
+		wantArgs    []string
+		wantVerbose bool
+		wantEnv     string
+	}
+	tests := []test{
+		{
+			name:        "dispatch with global flags",
+			args:        []string{"tool", "-v", "deploy", "--env=prod", "app", "-v"},
+			wantParseOk: true,
+			wantCommand: "deploy",
+			wantArgs:    []string{"app"},
+			wantVerbose: true,
+			wantEnv:     "prod",
+		},
+		{
+			name:        "global flag after command",
+			args:        []string{"tool", "status", "-v"},
+			wantParseOk: true,
+			wantCommand: "status",
+			wantArgs:    []string{},
+			wantVerbose: true,
+		},
+		{
+			name:        "command option is not global",
+			args:        []string{"tool", "--env=prod", "deploy"},
+			wantParseOk: false,
+			wantDone:    true,
+		},
+		{
+			name:        "unknown command",
+			args:        []string{"tool", "destroy"},
+			wantParseOk: false,
+			wantDone:    true,
+		},
+		{
+			name:        "missing command",
+			args:        []string{"tool", "-v"},
+			wantParseOk: false,
+			wantDone:    true,
+		},
+		{
+			name:        "command help",
+			args:        []string{"tool", "deploy", "-h"},
+			wantParseOk: true,
+			wantDone:    true,
+			wantCommand: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var gotCommand string
+			var gotArgs []string
+			handler := func(cmd *GetOpt, args []string) error {
+				gotCommand = cmd.name
+				gotArgs = args
+				return nil
+			}
+			opts := New().WithDefaults("tool", "v1").WithErrorHandler(func(err error, option Option) (bool, error) {
+				return true, err
+			})
+			verbose, _ := opts.Flag('v', "--verbose", "verbose output")
+			deploy := opts.Command("deploy", "deploy application", handler)
+			env, _ := deploy.StringDefault('e', "--env", "dev", "environment")
+			opts.Command("status", "show status", handler)
+			_, err := opts.Parse(test.args, false)
+			if (err == nil) != test.wantParseOk {
+				t.Errorf("Parse() error = %v, want ok = %v", err, test.wantParseOk)
+			}
+			if opts.Done() != test.wantDone {
+				t.Errorf("Done() = %v, want %v", opts.Done(), test.wantDone)
+			}
+			if !test.wantParseOk {
+				return
+			}
+			if gotCommand != test.wantCommand || !reflect.DeepEqual(gotArgs, test.wantArgs) {
+				t.Errorf("handler got %v %v, want %v %v", gotCommand, gotArgs, test.wantCommand, test.wantArgs)
+			}
+			if test.wantCommand != "" && opts.SelectedCommand().name != test.wantCommand {
+				t.Errorf("SelectedCommand() = %v", opts.SelectedCommand().name)
+			}
+			if *verbose != test.wantVerbose {
+				t.Errorf("verbose = %v, want %v", *verbose, test.wantVerbose)
+			}
+			if test.wantEnv != "" && *env != test.wantEnv {
+				t.Errorf("env = %v, want %v", *env, test.wantEnv)
+			}
+		})
+	}
+}
+
+func TestGetOpt_CommandErrorIndex(t *testing.T) {
+	var got Option
+	opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+		got = option
+		return true, err
+	})
+	_, _ = opts.Flag('v', "--verbose", "verbose")
+	cmd := opts.Command("run", "Run it", nil)
+	_, _ = cmd.StringValue('n', "--name", false, "name")
+	_, err := opts.Parse([]string{"prog", "-v", "run", "x", "-n"}, false)
+	if optErr := OptionErrors(err); len(optErr) != 1 || optErr[0].Index != 4 {
+		t.Errorf("Parse() error = %v", err)
+	}
+	if got.Index != 4 || got.Raw != "-n" {
+		t.Errorf("handler option = %+v", got)
+	}
+}
+
+func TestGetOpt_CommandAbbreviations(t *testing.T) {
+	opts := New()
+	cmd := opts.Command("run", "Run it", nil)
+	name, _ := cmd.StringValue('n', "--name", false, "name")
+	opts.SetAbbreviations(true)
+	if _, err := opts.Parse([]string{"prog", "run", "--na=x"}, false); err != nil || *name != "x" {
+		t.Errorf("Parse() = %v, name = %q", err, *name)
+	}
+}
@@ -0,0 +1,513 @@
+package getopt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+type completionOption struct {
+	short    []string
+	long     []string
+	arg      bool
+	optional bool
+	multiple bool
+	help     string
+	hint     string
+	choices  []string
+}
+
+func (opts *GetOpt) AddCompletion() {
+	opts.SetCompletion(true)
+	def := optDef{
+		longOpts: []string{"--completion"},
+		help:     "Print shell completion script",
+		choices:  completionShells,
+		argType:  strings.Join(completionShells, "|"),
+	}
+	def.argConv = func(arg string) error {
+		shell, err := matchChoice(completionShells, arg)
+		if err != nil {
+			return err
+		}
+		opts.done = true
+		return opts.WriteCompletion(opts.output(), shell)
+	}
+	_ = opts.safeAdd(def)
+}
+
+func (opts *GetOpt) WriteCompletion(w io.Writer, shell string) error {
+	root := opts
+	for root.parent != nil {
+		root = root.parent
+	}
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, root)
+	case "zsh":
+		writeZshCompletion(w, root)
+	case "fish":
+		writeFishCompletion(w, root)
+	default:
+		return errors.New("Unsupported shell `" + shell + "`, expected one of " + strings.Join(completionShells, ", "))
+	}
+	return nil
+}
+
+func (opts *GetOpt) completionOptions(inherit bool) []completionOption {
+	result := make([]completionOption, 0)
+	for cmd := opts; cmd != nil && (inherit || cmd == opts); cmd = cmd.parent {
+		for _, opt := range cmd.optionList {
+			if opt.hidden || (cmd != opts && opts.shadows(opt)) {
+				continue
+			}
+			option := completionOption{
+				arg:      !opt.noArg,
+				optional: opt.optionalArg,
+				multiple: opt.multiple,
+				help:     opt.help,
+				hint:     completionHint(opt),
+				choices:  opt.choices,
+			}
+			for _, f := range opt.posixOpts {
+				if f != 0 {
+					option.short = append(option.short, string(f))
+				}
+			}
+			for _, f := range opt.longOpts {
+				if strings.HasPrefix(f, "--") {
+					option.long = append(option.long, f[2:])
+				}
+			}
+			result = append(result, option)
+			if len(opt.negated) > 0 {
+				negated := completionOption{help: opt.help}
+				for _, f := range opt.negated {
+					negated.long = append(negated.long, f[2:])
+				}
+				result = append(result, negated)
+			}
+		}
+	}
+	return result
+}
+
+func completionHint(opt *optDef) string {
+	switch {
+	case opt.noArg:
+		return ""
+	case len(opt.choices) > 0:
+		return "choice"
+	case slices.Contains([]string{"int", "uint", "float", "duration", "time"}, strings.TrimPrefix(opt.argType, "key:")):
+		return "none"
+	case opt.argType == "dir":
+		return "dir"
+	default:
+		return "file"
+	}
+}
+
+func (option completionOption) words() []string {
+	words := make([]string, 0)
+	for _, f := range option.short {
+		words = append(words, "-"+f)
+	}
+	for _, f := range option.long {
+		words = append(words, "--"+f)
+	}
+	return words
+}
+
+func completionTree(opts *GetOpt, path []string, visit func(cmd *GetOpt, path []string)) {
+	visit(opts, path)
+	for _, cmd := range opts.commands {
+		completionTree(cmd, append(path[:len(path):len(path)], cmd.name), visit)
+	}
+}
+
+func completionIdent(path []string) string {
+	return "_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, strings.Join(path, "_"))
+}
+
+func shellQuote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
+
+func writeBashCompletion(w io.Writer, root *GetOpt) {
+	fmt.Fprintf(w, "# bash completion for %s\n", root.name)
+	fmt.Fprintf(w, "%s() {\n", completionIdent([]string{root.name}))
+	fmt.Fprintln(w, `    local cur prev path="" i`)
+	fmt.Fprintln(w, `    cur="${COMP_WORDS[COMP_CWORD]}"`)
+	fmt.Fprintln(w, `    prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	fmt.Fprintln(w, `    if [[ "$cur" == "=" ]]; then`)
+	fmt.Fprintln(w, `        cur=""`)
+	fmt.Fprintln(w, `    elif [[ "$prev" == "=" ]]; then`)
+	fmt.Fprintln(w, `        prev="${COMP_WORDS[COMP_CWORD-2]}"`)
+	fmt.Fprintln(w, `    fi`)
+	if len(root.commands) > 0 {
+		fmt.Fprintln(w, `    for ((i=1; i<COMP_CWORD; i++)); do`)
+		fmt.Fprintln(w, `        case "$path:${COMP_WORDS[i]}" in`)
+		completionTree(root, nil, func(cmd *GetOpt, path []string) {
+			if len(path) > 0 {
+				parent := strings.Join(path[:len(path)-1], " ")
+				fmt.Fprintf(w, "        %s) path=%s ;;\n", shellQuote(parent+":"+cmd.name), shellQuote(strings.Join(path, " ")))
+			}
+		})
+		fmt.Fprintln(w, `        esac`)
+		fmt.Fprintln(w, `    done`)
+	}
+	fmt.Fprintln(w, `    case "$path:$prev" in`)
+	completionTree(root, nil, func(cmd *GetOpt, path []string) {
+		prefix := strings.Join(path, " ") + ":"
+		for _, option := range cmd.completionOptions(true) {
+			if !option.arg || option.optional {
+				continue
+			}
+			patterns := make([]string, 0)
+			for _, word := range option.words() {
+				patterns = append(patterns, shellQuote(prefix+word))
+			}
+			fmt.Fprintf(w, "        %s) %s; return ;;\n", strings.Join(patterns, "|"), bashReply(option))
+		}
+	})
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w, `    case "$path" in`)
+	completionTree(root, nil, func(cmd *GetOpt, path []string) {
+		words := make([]string, 0)
+		for _, option := range cmd.completionOptions(true) {
+			words = append(words, option.words()...)
+		}
+		fmt.Fprintf(w, "        %s)\n", shellQuote(strings.Join(path, " ")))
+		fmt.Fprintln(w, `            if [[ "$cur" == -* ]]; then`)
+		fmt.Fprintf(w, "                COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(words, " ")))
+		fmt.Fprintln(w, `            else`)
+		if len(cmd.commands) > 0 {
+			names := make([]string, 0)
+			for _, sub := range cmd.commands {
+				names = append(names, sub.name)
+			}
+			fmt.Fprintf(w, "                COMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(names, " ")))
+		} else {
+			fmt.Fprintln(w, `                COMPREPLY=($(compgen -f -- "$cur"))`)
+		}
+		fmt.Fprintln(w, `            fi`)
+		fmt.Fprintln(w, `            ;;`)
+	})
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w, `}`)
+	fmt.Fprintf(w, "complete -F %s %s\n", completionIdent([]string{root.name}), root.name)
+}
+
+func bashReply(option completionOption) string {
+	switch option.hint {
+	case "choice":
+		return `COMPREPLY=($(compgen -W ` + shellQuote(strings.Join(option.choices, " ")) + ` -- "$cur"))`
+	case "none":
+		return `COMPREPLY=()`
+	case "dir":
+		return `COMPREPLY=($(compgen -d -- "$cur"))`
+	default:
+		return `COMPREPLY=($(compgen -f -- "$cur"))`
+	}
+}
+
+func writeZshCompletion(w io.Writer, root *GetOpt) {
+	fmt.Fprintf(w, "#compdef %s\n", root.name)
+	completionTree(root, []string{root.name}, func(cmd *GetOpt, path []string) {
+		fmt.Fprintf(w, "\n%s() {\n", completionIdent(path))
+		if len(cmd.commands) > 0 {
+			fmt.Fprintln(w, `    local context state state_descr line`)
+			fmt.Fprintln(w, `    typeset -A opt_args`)
+			fmt.Fprintln(w, `    _arguments -C -s -S \`)
+		} else {
+			fmt.Fprintln(w, `    _arguments -s -S \`)
+		}
+		for _, option := range cmd.completionOptions(true) {
+			for _, spec := range zshSpecs(option) {
+				fmt.Fprintf(w, "        %s \\\n", shellQuote(spec))
+			}
+		}
+		if len(cmd.commands) == 0 {
+			fmt.Fprintln(w, `        '*:file:_files'`)
+			fmt.Fprintln(w, `}`)
+			return
+		}
+		fmt.Fprintln(w, `        '1: :->command' \`)
+		fmt.Fprintln(w, `        '*:: :->args'`)
+		fmt.Fprintln(w, `    case $state in`)
+		fmt.Fprintln(w, `        command)`)
+		fmt.Fprintln(w, `            local -a commands`)
+		fmt.Fprintln(w, `            commands=(`)
+		for _, sub := range cmd.commands {
+			fmt.Fprintf(w, "                %s\n", shellQuote(strings.ReplaceAll(sub.name, ":", `\:`)+":"+sub.commandHelp))
+		}
+		fmt.Fprintln(w, `            )`)
+		fmt.Fprintln(w, `            _describe -t commands 'command' commands`)
+		fmt.Fprintln(w, `            ;;`)
+		fmt.Fprintln(w, `        args)`)
+		fmt.Fprintln(w, `            case $line[1] in`)
+		for _, sub := range cmd.commands {
+			fmt.Fprintf(w, "                %s) %s ;;\n", shellQuote(sub.name), completionIdent(append(path[:len(path):len(path)], sub.name)))
+		}
+		fmt.Fprintln(w, `            esac`)
+		fmt.Fprintln(w, `            ;;`)
+		fmt.Fprintln(w, `    esac`)
+		fmt.Fprintln(w, `}`)
+	})
+	fmt.Fprintf(w, "\n%s \"$@\"\n", completionIdent([]string{root.name}))
+}
+
+func zshSpecs(option completionOption) []string {
+	words := option.words()
+	exclusion := ""
+	if option.multiple {
+		exclusion = "*"
+	} else if len(words) > 1 {
+		exclusion = "(" + strings.Join(words, " ") + ")"
+	}
+	help := "[" + strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(option.help) + "]"
+	action := ""
+	if option.arg {
+		separator := ":"
+		if option.optional {
+			separator = "::"
+		}
+		action = separator + "value:" + zshAction(option)
+	}
+	specs := make([]string, 0)
+	for _, f := range option.short {
+		suffix := ""
+		if option.arg && option.optional {
+			suffix = "-"
+		} else if option.arg {
+			suffix = "+"
+		}
+		specs = append(specs, exclusion+"-"+f+suffix+help+action)
+	}
+	for _, f := range option.long {
+		suffix := ""
+		if option.arg && option.optional {
+			suffix = "=-"
+		} else if option.arg {
+			suffix = "="
+		}
+		specs = append(specs, exclusion+"--"+f+suffix+help+action)
+	}
+	return specs
+}
+
+func zshAction(option completionOption) string {
+	switch option.hint {
+	case "choice":
+		return "(" + strings.Join(option.choices, " ") + ")"
+	case "none":
+		return " "
+	case "dir":
+		return "_files -/"
+	default:
+		return "_files"
+	}
+}
+
+func writeFishCompletion(w io.Writer, root *GetOpt) {
+	fmt.Fprintf(w, "# fish completion for %s\n", root.name)
+	completionTree(root, nil, func(cmd *GetOpt, path []string) {
+		condition := ""
+		if len(path) > 0 {
+			condition = " -n " + fishQuote("__fish_seen_subcommand_from "+path[len(path)-1])
+		}
+		for _, sub := range cmd.commands {
+			subCondition := " -n '__fish_use_subcommand'"
+			if len(path) > 0 {
+				subCondition = condition
+			}
+			fmt.Fprintf(w, "complete -c %s%s -f -a %s -d %s\n", root.name, subCondition, fishQuote(sub.name), fishQuote(sub.commandHelp))
+		}
+		for _, option := range cmd.completionOptions(false) {
+			line := "complete -c " + root.name + condition
+			for _, f := range option.short {
+				line += " -s " + f
+			}
+			for _, f := range option.long {
+				line += " -l " + f
+			}
+			if option.help != "" {
+				line += " -d " + fishQuote(option.help)
+			}
+			if option.arg && !option.optional {
+				switch option.hint {
+				case "choice":
+					line += " -x -a " + fishQuote(strings.Join(option.choices, " "))
+				case "none":
+					line += " -x"
+				case "dir":
+					line += " -x -a '(__fish_complete_directories)'"
+				default:
+					line += " -r -F"
+				}
+			}
+			fmt.Fprintln(w, line)
+		}
+	})
+}
+
+func fishQuote(text string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(text) + "'"
+}
+
+const completeCommand = "__complete"
+
+func (opts *GetOpt) SetCompletion(enable bool) {
+	opts.completion = enable
+}
+
+func (opts *GetOpt) WithCompletion(enable bool) *GetOpt {
+	opts.SetCompletion(enable)
+	return opts
+}
+
+func (opts *GetOpt) Completer(name string, completer func(prefix string) []string) error {
+	if item, found := opts.optionMap[name]; found {
+		item.completer = completer
+		return nil
+	}
+	for _, pos := range opts.positionals {
+		if pos.name == name {
+			pos.completer = completer
+			return nil
+		}
+	}
+	return errors.New("Unknown option or argument `" + name + "`")
+}
+
+func (opts *GetOpt) writeCandidates(w io.Writer, words []string) error {
+	candidates, directive := opts.complete(words)
+	for _, candidate := range candidates {
+		if _, err := fmt.Fprintln(w, candidate); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, ":"+directive)
+	return err
+}
+
+func (opts *GetOpt) complete(words []string) ([]string, string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	cmd := opts
+	position := 0
+	var pending *optDef
+	dashdash := false
+	for _, word := range words[:len(words)-1] {
+		if pending != nil {
+			pending = nil
+		} else if !dashdash && word == "--" {
+			dashdash = true
+		} else if !dashdash && strings.HasPrefix(word, "--") {
+			if item, found := cmd.lookup(word); found && !item.noArg && !item.optionalArg && !slices.Contains(item.negated, word) {
+				pending = item
+			}
+		} else if !dashdash && len(word) > 1 && word[0] == '-' {
+			flags := []rune(word)[1:]
+			for i, f := range flags {
+				if item, found := cmd.lookup("-" + string(f)); found && !item.noArg {
+					if i+1 == len(flags) && !item.optionalArg {
+						pending = item
+					}
+					break
+				}
+			}
+		} else if sub := cmd.findCommand(word); sub != nil && !dashdash {
+			cmd, position = sub, 0
+		} else {
+			position++
+		}
+	}
+	if pending != nil {
+		return pending.completeValue("", cur)
+	} else if !dashdash && strings.HasPrefix(cur, "--") && strings.Contains(cur, "=") {
+		eq := strings.Index(cur, "=")
+		if item, found := cmd.lookup(cur[:eq]); found && !item.noArg {
+			return item.completeValue(cur[:eq+1], cur[eq+1:])
+		}
+		return nil, "none"
+	} else if !dashdash && strings.HasPrefix(cur, "-") {
+		candidates := make([]string, 0)
+		for _, option := range cmd.completionOptions(true) {
+			for _, word := range option.words() {
+				if strings.HasPrefix(word, cur) {
+					candidates = append(candidates, candidate(word, option.help))
+				}
+			}
+		}
+		return candidates, "none"
+	} else if len(cmd.commands) > 0 {
+		candidates := make([]string, 0)
+		for _, sub := range cmd.commands {
+			if strings.HasPrefix(sub.name, cur) {
+				candidates = append(candidates, candidate(sub.name, sub.commandHelp))
+			}
+		}
+		return candidates, "none"
+	}
+	for _, pos := range cmd.positionals {
+		if position < pos.max || (pos.variadic && pos.max == 0) {
+			if pos.completer != nil {
+				return filterCandidates(pos.completer(cur), "", cur), "none"
+			}
+			break
+		}
+		position -= pos.max
+	}
+	return nil, "files"
+}
+
+func (opts *GetOpt) findCommand(name string) *GetOpt {
+	for _, cmd := range opts.commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (opt *optDef) completeValue(prefix string, cur string) ([]string, string) {
+	switch hint := completionHint(opt); {
+	case opt.completer != nil:
+		return filterCandidates(opt.completer(cur), prefix, cur), "none"
+	case hint == "choice":
+		return filterCandidates(opt.choices, prefix, cur), "none"
+	case hint == "file" || hint == "dir":
+		return nil, hint + "s"
+	default:
+		return nil, "none"
+	}
+}
+
+func filterCandidates(values []string, prefix string, cur string) []string {
+	candidates := make([]string, 0)
+	for _, value := range values {
+		if strings.HasPrefix(value, cur) {
+			candidates = append(candidates, prefix+value)
+		}
+	}
+	return candidates
+}
+
+func candidate(value string, help string) string {
+	if help == "" {
+		return value
+	}
+	return value + "\t" + help
+}
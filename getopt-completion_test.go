@@ -0,0 +1,242 @@
+package getopt
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func completionFixture() *GetOpt {
+	opts := New().WithDefaults("tool", "1.0")
+	opts.AddCompletion()
+	_, _ = opts.Flag('v', "--verbose", "Verbose output")
+	_ = opts.Negatable("--verbose")
+	_, _ = opts.Choice('f', "--format", []string{"json", "yaml"}, "json", "Output [format]")
+	_, _ = opts.IntOptional('O', "--level", 0, 2, "Optimization level")
+	deploy := opts.Command("deploy", "Deploy application", nil)
+	_, _ = deploy.StringValue('c', "--config", false, "Config file")
+	_, _ = deploy.IntValue('n', "--replicas", false, "Replica count")
+	_, _ = deploy.StringList('t', "--tag", "Tag, can't repeat")
+	_ = opts.Command("status", "Show status", nil)
+	return opts
+}
+
+func TestGetOpt_WriteCompletion(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{
+			"complete -F _tool tool\n",
+			`        ':deploy') path='deploy' ;;`,
+			`        ':-f'|':--format') COMPREPLY=($(compgen -W 'json yaml' -- "$cur")); return ;;`,
+			`        'deploy:-n'|'deploy:--replicas') COMPREPLY=(); return ;;`,
+			`        'deploy:-c'|'deploy:--config') COMPREPLY=($(compgen -f -- "$cur")); return ;;`,
+			`                COMPREPLY=($(compgen -W 'deploy status' -- "$cur"))`,
+			`--verbose --no-verbose`,
+		}},
+		{"zsh", []string{
+			"#compdef tool\n",
+			`        '(-f --format)--format=[Output \[format\]]:value:(json yaml)' \`,
+			`        '(-O --level)-O-[Optimization level]::value: ' \`,
+			`        '(-O --level)--level=-[Optimization level]::value: ' \`,
+			`        '--no-verbose[Verbose output]' \`,
+			`                'deploy') _tool_deploy ;;`,
+			`        '(-c --config)-c+[Config file]:value:_files' \`,
+			"                'status:Show status'\n",
+			"\n_tool \"$@\"\n",
+		}},
+		{"fish", []string{
+			"complete -c tool -n '__fish_use_subcommand' -f -a 'deploy' -d 'Deploy application'\n",
+			"complete -c tool -s f -l format -d 'Output [format]' -x -a 'json yaml'\n",
+			"complete -c tool -n '__fish_seen_subcommand_from deploy' -s c -l config -d 'Config file' -r -F\n",
+			"complete -c tool -n '__fish_seen_subcommand_from deploy' -s t -l tag -d 'Tag, can\\'t repeat' -r -F\n",
+			"complete -c tool -l no-verbose -d 'Verbose output'\n",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.shell, func(t *testing.T) {
+			var buf strings.Builder
+			if err := completionFixture().WriteCompletion(&buf, test.shell); err != nil {
+				t.Fatalf("WriteCompletion() unexpected error %v", err)
+			}
+			got := buf.String()
+			for _, want := range test.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("WriteCompletion(%v) missing %q in\n%s", test.shell, want, got)
+				}
+			}
+			if shell, err := exec.LookPath(test.shell); err == nil {
+				script := filepath.Join(t.TempDir(), "completion")
+				_ = os.WriteFile(script, []byte(got), 0o644)
+				if out, err := exec.Command(shell, "-n", script).CombinedOutput(); err != nil {
+					t.Errorf("%v -n failed: %v\n%s", test.shell, err, out)
+				}
+			}
+		})
+	}
+	if err := New().WriteCompletion(&strings.Builder{}, "tcsh"); err == nil {
+		t.Errorf("WriteCompletion(tcsh) expected to fail")
+	}
+}
+
+func TestGetOpt_AddCompletion(t *testing.T) {
+	var stdout strings.Builder
+	opts := completionFixture().WithOutput(&stdout, nil)
+	if _, err := opts.Parse([]string{"tool", "--completion=fi"}, false); err != nil || !opts.Done() {
+		t.Fatalf("Parse() error = %v, done = %v", err, opts.Done())
+	}
+	if !strings.HasPrefix(stdout.String(), "# fish completion for tool\n") {
+		t.Errorf("--completion output = %q", stdout.String())
+	}
+	if !strings.Contains(opts.HelpString(), "--completion=bash|zsh|fish") {
+		t.Errorf("Help() should list completion shells")
+	}
+}
+
+func TestGetOpt_Complete(t *testing.T) {
+	opts := completionFixture()
+	deploy := opts.findCommand("deploy")
+	_, _ = deploy.StringValue('N', "--namespace", false, "Namespace")
+	_, _ = deploy.StringPositional("APP", true, "Application")
+	_ = deploy.Completer("--namespace", func(prefix string) []string {
+		return []string{"default", "kube-system", "kube-public"}
+	})
+	_ = deploy.Completer("APP", func(prefix string) []string {
+		return []string{"web", "worker"}
+	})
+	tests := []struct {
+		name  string
+		words []string
+		want  string
+	}{
+		{"commands", []string{""}, "deploy\tDeploy application\nstatus\tShow status\n:none\n"},
+		{"options", []string{"--fo"}, "--format\tOutput [format]\n:none\n"},
+		{"choices", []string{"-f", ""}, "json\nyaml\n:none\n"},
+		{"choices inline", []string{"--format=y"}, "--format=yaml\n:none\n"},
+		{"number", []string{"deploy", "-n", ""}, ":none\n"},
+		{"files", []string{"deploy", "--config", ""}, ":files\n"},
+		{"completer", []string{"-v", "deploy", "--namespace", "kube-"}, "kube-system\nkube-public\n:none\n"},
+		{"completer bundled", []string{"deploy", "-vN", "d"}, "default\n:none\n"},
+		{"positional completer", []string{"deploy", "-c", "x", "w"}, "web\nworker\n:none\n"},
+		{"beyond positionals", []string{"deploy", "web", ""}, ":files\n"},
+		{"global options in command", []string{"deploy", "--verb"}, "--verbose\tVerbose output\n:none\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var stdout strings.Builder
+			opts.SetOutput(&stdout, nil)
+			args := append([]string{"tool", "__complete"}, test.words...)
+			if _, err := opts.Parse(args, false); err != nil || !opts.Done() {
+				t.Fatalf("Parse() error = %v, done = %v", err, opts.Done())
+			}
+			if got := stdout.String(); got != test.want {
+				t.Errorf("Parse(%q) = %q, want %q", args, got, test.want)
+			}
+		})
+	}
+	if err := opts.Completer("--unknown", nil); err == nil {
+		t.Errorf("Completer() expected to fail for unknown option")
+	}
+}
+
+func TestGetOpt_CompletionDisabled(t *testing.T) {
+	var stdout strings.Builder
+	opts := New().WithOutput(&stdout, nil)
+	name, _ := opts.StringPositional("NAME", true, "Name")
+	if args, err := opts.Parse([]string{"prog", "__complete"}, false); err != nil || opts.Done() || *name != "__complete" || len(args) != 1 {
+		t.Errorf("Parse() = %q, error = %v, done = %v", args, err, opts.Done())
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("Parse() unexpected output %q", stdout.String())
+	}
+	opts = New().WithErrorHandler(func(err error, option Option) (bool, error) {
+		return true, err
+	})
+	_, _ = opts.StringPositional("NAME", true, "Name")
+	_, _ = opts.StringPositional("OTHER", true, "Other")
+	if _, err := opts.Parse([]string{"prog", "__complete"}, false); err == nil {
+		t.Errorf("Parse() expected to report missing argument OTHER")
+	}
+}
+
+type testCompleteTarget struct {
+	Namespace string `flag:"n,namespace" complete:"Namespaces"`
+	App       string `arg:"0" complete:"Apps"`
+}
+
+func (target *testCompleteTarget) Namespaces(prefix string) []string {
+	return []string{"default", "prod"}
+}
+
+func (target *testCompleteTarget) Apps(prefix string) []string {
+	return []string{"web"}
+}
+
+func TestGetOpt_MarshalComplete(t *testing.T) {
+	var stdout strings.Builder
+	opts := New().WithOutput(&stdout, nil).WithCompletion(true)
+	if _, err := opts.Marshal(&testCompleteTarget{}, []string{"prog", "__complete", "-n", "p"}, false); err != nil {
+		t.Fatalf("Marshal() unexpected error %v", err)
+	}
+	if got := stdout.String(); got != "prod\n:none\n" {
+		t.Errorf("Marshal() completion = %q", got)
+	}
+	stdout.Reset()
+	if _, err := New().WithOutput(&stdout, nil).WithCompletion(true).Marshal(&testCompleteTarget{}, []string{"prog", "__complete", ""}, false); err != nil {
+		t.Fatalf("Marshal() unexpected error %v", err)
+	}
+	if got := stdout.String(); got != "web\n:none\n" {
+		t.Errorf("Marshal() completion = %q", got)
+	}
+	type missing struct {
+		Namespace string `flag:"n" complete:"Nope"`
+	}
+	if err := New().bind(&missing{}); err == nil {
+		t.Errorf("bind() expected to fail for missing completer method")
+	}
+}
+
+func TestGetOpt_MarshalCompletionHint(t *testing.T) {
+	type target struct {
+		Count   int               `flag:"n,count"`
+		Ratio   []float64         `flag:"r,ratio"`
+		Timeout time.Duration     `flag:"t,timeout"`
+		Limits  map[string]uint   `flag:"l,limit"`
+		Config  string            `flag:"c,config"`
+		Input   io.Reader         `flag:"i,input"`
+		Dir     string            `flag:"d,dir" type:"dir"`
+		Labels  map[string]string `flag:"label"`
+	}
+	opts := New()
+	if err := opts.bind(&target{}); err != nil {
+		t.Fatalf("bind() unexpected error %v", err)
+	}
+	tests := []struct {
+		option   string
+		wantType string
+		wantHint string
+	}{
+		{"--count", "int", "none"},
+		{"--ratio", "float", "none"},
+		{"--timeout", "duration", "none"},
+		{"--limit", "key:uint", "none"},
+		{"--config", "string", "file"},
+		{"--input", "file", "file"},
+		{"--dir", "dir", "dir"},
+		{"--label", "key:string", "file"},
+	}
+	for _, test := range tests {
+		opt := opts.optionMap[test.option]
+		if opt.argType != test.wantType || completionHint(opt) != test.wantHint {
+			t.Errorf("%s: argType = %q, hint = %q", test.option, opt.argType, completionHint(opt))
+		}
+	}
+	if _, directive := opts.complete([]string{"--dir", ""}); directive != "dirs" {
+		t.Errorf("complete() directive = %q, want dirs", directive)
+	}
+}
@@ -0,0 +1,82 @@
+package getopt
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func (opts *GetOpt) Counter(flag rune, longFlag string, max int, help string) (*int, error) {
+	return opts.CounterV([]rune{flag}, []string{longFlag}, max, help)
+}
+
+func (opts *GetOpt) CounterV(flags []rune, longFlags []string, max int, help string) (*int, error) {
+	var result int
+	return &result, opts.counter(&result, 0, flags, longFlags, max, help)
+}
+
+func (opts *GetOpt) Decrement(counter *int, flag rune, longFlag string, help string) error {
+	return opts.DecrementV(counter, []rune{flag}, []string{longFlag}, help)
+}
+
+func (opts *GetOpt) DecrementV(counter *int, flags []rune, longFlags []string, help string) error {
+	def := optDef{
+		posixOpts: flags,
+		longOpts:  longFlags,
+		help:      help,
+		noArg:     true,
+		multiple:  true,
+	}
+	def.argConv = func(string) error {
+		if *counter > 0 {
+			*counter--
+		}
+		return nil
+	}
+	return opts.safeAdd(def)
+}
+
+func (opts *GetOpt) counter(result *int, value int, flags []rune, longFlags []string, max int, help string) error {
+	*result = value
+	def := optDef{
+		posixOpts: flags,
+		longOpts:  longFlags,
+		help:      help,
+		noArg:     true,
+		multiple:  true,
+	}
+	def.argConv = func(string) error {
+		if max <= 0 || *result < max {
+			*result++
+		}
+		return nil
+	}
+	def.argReset = func() {
+		*result = value
+	}
+	return opts.safeAdd(def)
+}
+
+func (opts *GetOpt) bindCounter(fieldType reflect.StructField, fieldValue reflect.Value, flags []rune, longopts []string, help string) error {
+	var err error
+	max, value := 0, 0
+	if found, ok := fieldType.Tag.Lookup("max"); ok {
+		if max, err = strconv.Atoi(found); err != nil {
+			return err
+		}
+	}
+	if found, ok := fieldType.Tag.Lookup("default"); ok {
+		if value, err = strconv.Atoi(found); err != nil {
+			return err
+		}
+	}
+	result := fieldValue.Addr().Interface().(*int)
+	if err = opts.counter(result, value, flags, longopts, max, help); err != nil {
+		return err
+	}
+	if found, ok := fieldType.Tag.Lookup("decrement"); ok {
+		decFlags, decLongopts := opts.separateFlagsFromLognopts(strings.Split(found, ","))
+		err = opts.DecrementV(result, decFlags, decLongopts, "Decrement "+help)
+	}
+	return err
+}
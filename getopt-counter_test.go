@@ -0,0 +1,67 @@
+package getopt
+
+import (
+	"testing"
+)
+
+func TestGetOpt_Counter(t *testing.T) {
+	type test struct {
+		name        string
+		max         int
+		args        []string
+		wantValue   int
+		wantParseOk bool
+	}
+	tests := []test{
+		{"not given", 0, []string{"prog"}, 0, true},
+		{"once", 0, []string{"prog", "-v"}, 1, true},
+		{"bundled", 0, []string{"prog", "-vvv"}, 3, true},
+		{"separate", 0, []string{"prog", "-v", "--verbose", "-v"}, 3, true},
+		{"capped", 2, []string{"prog", "-vvvv"}, 2, true},
+		{"decrement", 0, []string{"prog", "-vvv", "-q"}, 2, true},
+		{"decrement stops at zero", 0, []string{"prog", "-qq", "-v"}, 1, true},
+		{"no argument allowed", 0, []string{"prog", "--verbose=2"}, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+				return true, err
+			})
+			result, err := opts.Counter('v', "--verbose", test.max, "verbosity")
+			if err != nil {
+				t.Fatalf("Unexpected error %v on setup", err)
+			}
+			if err := opts.Decrement(result, 'q', "--quiet", "less verbose"); err != nil {
+				t.Fatalf("Unexpected error %v on setup", err)
+			}
+			if _, err := opts.Parse(test.args, false); (err == nil) != test.wantParseOk {
+				t.Errorf("Parse() error = %v, want ok = %v", err, test.wantParseOk)
+			} else if err == nil && *result != test.wantValue {
+				t.Errorf("Unexpected value: %v (expected: %v)", *result, test.wantValue)
+			}
+		})
+	}
+}
+
+func TestGetOpt_MarshalCounter(t *testing.T) {
+	type target struct {
+		Verbose int `flag:"v,verbose" count:"true" max:"3" decrement:"q,quiet" default:"1"`
+	}
+	tests := []struct {
+		args      []string
+		wantValue int
+	}{
+		{[]string{"prog"}, 1},
+		{[]string{"prog", "-vv"}, 3},
+		{[]string{"prog", "-vvvvv"}, 3},
+		{[]string{"prog", "--quiet"}, 0},
+	}
+	for _, test := range tests {
+		result := target{}
+		if _, err := New().Marshal(&result, test.args, false); err != nil {
+			t.Errorf("Marshal(%v) unexpected error %v", test.args, err)
+		} else if result.Verbose != test.wantValue {
+			t.Errorf("Marshal(%v) = %v, want %v", test.args, result.Verbose, test.wantValue)
+		}
+	}
+}
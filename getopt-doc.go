@@ -0,0 +1,193 @@
+package getopt
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type docSection struct {
+	title  string
+	header []string
+	rows   [][]string
+	links  []string
+}
+
+func (opts *GetOpt) WriteMarkdown(w io.Writer) error {
+	return writeBuffered(w, opts.writeMarkdown)
+}
+
+func (opts *GetOpt) WriteHTML(w io.Writer) error {
+	return writeBuffered(w, opts.writeHTML)
+}
+
+func writeBuffered(w io.Writer, write func(w io.Writer)) error {
+	var buf strings.Builder
+	write(&buf)
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+func (opts *GetOpt) writeMarkdown(w io.Writer) {
+	name := opts.fullName()
+	fmt.Fprintf(w, "# %s\n\n", name)
+	if opts.commandHelp != "" {
+		fmt.Fprintf(w, "%s\n\n", opts.commandHelp)
+	}
+	for _, desc := range opts.description {
+		fmt.Fprintf(w, "%s  \n", desc)
+	}
+	if len(opts.description) > 0 {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "```\n%s\n```\n", strings.Join(append([]string{name}, opts.synopsisArgs()...), " "))
+	for _, section := range opts.docSections(".md") {
+		fmt.Fprintf(w, "\n## %s\n\n", section.title)
+		if section.header == nil {
+			for _, row := range section.rows {
+				fmt.Fprintf(w, "- %s\n", row[0])
+			}
+			continue
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(section.header, " | "))
+		fmt.Fprintf(w, "|%s\n", strings.Repeat("---|", len(section.header)))
+		for i, row := range section.rows {
+			cells := make([]string, len(row))
+			for j, cell := range row {
+				if cell != "" && j == 0 {
+					cell = "`" + strings.ReplaceAll(cell, ", ", "`, `") + "`"
+				}
+				cells[j] = strings.ReplaceAll(cell, "|", `\|`)
+			}
+			if section.links != nil && section.links[i] != "" {
+				cells[0] = "[" + cells[0] + "](" + section.links[i] + ")"
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+}
+
+func (opts *GetOpt) writeHTML(w io.Writer) {
+	name := html.EscapeString(opts.fullName())
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n", name)
+	fmt.Fprintf(w, "<h1>%s</h1>\n", name)
+	if opts.commandHelp != "" {
+		fmt.Fprintf(w, "<p>%s</p>\n", html.EscapeString(opts.commandHelp))
+	}
+	if len(opts.description) > 0 {
+		lines := make([]string, 0)
+		for _, desc := range opts.description {
+			lines = append(lines, html.EscapeString(desc))
+		}
+		fmt.Fprintf(w, "<p>%s</p>\n", strings.Join(lines, "<br>\n"))
+	}
+	fmt.Fprintf(w, "<pre>%s</pre>\n", html.EscapeString(strings.Join(append([]string{opts.fullName()}, opts.synopsisArgs()...), " ")))
+	for _, section := range opts.docSections(".html") {
+		fmt.Fprintf(w, "<h2>%s</h2>\n", html.EscapeString(section.title))
+		if section.header == nil {
+			fmt.Fprintln(w, "<ul>")
+			for _, row := range section.rows {
+				fmt.Fprintf(w, "<li>%s</li>\n", html.EscapeString(row[0]))
+			}
+			fmt.Fprintln(w, "</ul>")
+			continue
+		}
+		fmt.Fprintln(w, "<table>")
+		fmt.Fprintf(w, "<tr><th>%s</th></tr>\n", strings.Join(section.header, "</th><th>"))
+		for i, row := range section.rows {
+			cells := make([]string, len(row))
+			for j, cell := range row {
+				cells[j] = html.EscapeString(cell)
+				if cell != "" && j == 0 {
+					cells[j] = "<code>" + cells[j] + "</code>"
+				}
+			}
+			if section.links != nil && section.links[i] != "" {
+				cells[0] = "<a href=\"" + html.EscapeString(section.links[i]) + "\">" + cells[0] + "</a>"
+			}
+			fmt.Fprintf(w, "<tr><td>%s</td></tr>\n", strings.Join(cells, "</td><td>"))
+		}
+		fmt.Fprintln(w, "</table>")
+	}
+	fmt.Fprintln(w, "</body>\n</html>")
+}
+
+func (opts *GetOpt) WriteDocPages(dir string, format string) error {
+	write := opts.WriteMarkdown
+	if format == "html" {
+		write = opts.WriteHTML
+	} else if format != "md" {
+		return errors.New("Unsupported format `" + format + "`, expected one of md, html")
+	}
+	file, err := os.Create(filepath.Join(dir, docPage(opts, "."+format)))
+	if err != nil {
+		return err
+	}
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	for _, cmd := range opts.commands {
+		if err != nil {
+			break
+		}
+		err = cmd.WriteDocPages(dir, format)
+	}
+	return err
+}
+
+func (opts *GetOpt) docSections(ext string) []docSection {
+	sections := make([]docSection, 0)
+	header := []string{"Option", "Argument", "Default", "Environment", "Description"}
+	own := docSection{title: "Options", header: header}
+	for _, opt := range opts.optionList {
+		if !opt.hidden {
+			own.rows = append(own.rows, docOptionRow(opt))
+		}
+	}
+	global := docSection{title: "Global options", header: header}
+	for cmd := opts.parent; cmd != nil; cmd = cmd.parent {
+		for _, opt := range cmd.optionList {
+			if !opt.hidden && !opts.shadows(opt) {
+				global.rows = append(global.rows, docOptionRow(opt))
+			}
+		}
+	}
+	arguments := docSection{title: "Arguments", header: []string{"Argument", "Environment", "Description"}}
+	for _, pos := range opts.positionals {
+		arguments.rows = append(arguments.rows, []string{pos.String(), strings.Join(pos.env, ", "), pos.help})
+	}
+	constraints := docSection{title: "Constraints"}
+	for _, group := range opts.groups {
+		constraints.rows = append(constraints.rows, []string{group.String()})
+	}
+	commands := docSection{title: "Commands", header: []string{"Command", "Description"}, links: make([]string, 0)}
+	for _, cmd := range opts.commands {
+		commands.rows = append(commands.rows, []string{cmd.name, cmd.commandHelp})
+		commands.links = append(commands.links, docPage(cmd, ext))
+	}
+	for _, section := range []docSection{own, global, arguments, constraints, commands} {
+		if len(section.rows) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+func docOptionRow(opt *optDef) []string {
+	names, _ := optionNames(opt)
+	names = append(names, negatedNames(opt)...)
+	arg := opt.argType
+	if arg != "" && opt.optionalArg {
+		arg = "[" + arg + "]"
+	}
+	return []string{strings.Join(names, ", "), arg, opt.defValue, strings.Join(opt.env, ", "), optionDesc(opt)}
+}
+
+func docPage(opts *GetOpt, ext string) string {
+	return strings.ReplaceAll(opts.fullName(), " ", "-") + ext
+}
@@ -0,0 +1,103 @@
+package getopt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func docFixture() *GetOpt {
+	type target struct {
+		Format string `flag:"f,format" choices:"json,yaml" default:"json" env:"TOOL_FORMAT" help:"Output format"`
+		Level  int    `flag:"O,level" implicit:"2" help:"Optimization level"`
+	}
+	opts := New().WithDefaults("tool", "1.0", "Manages <deployments>.")
+	_ = opts.bind(&target{})
+	deploy := opts.Command("deploy", "Deploy application", nil)
+	_, _ = deploy.StringValue('c', "--config", true, "Config file")
+	_, _ = deploy.StringPositional("APP", true, "Application")
+	_ = deploy.Env("APP", "TOOL_APP")
+	return opts
+}
+
+func TestGetOpt_WriteMarkdown(t *testing.T) {
+	var buf strings.Builder
+	if err := docFixture().WriteMarkdown(&buf); err != nil {
+		t.Fatalf("WriteMarkdown() unexpected error %v", err)
+	}
+	want := "# tool\n\n" +
+		"Manages <deployments>.  \n\n" +
+		"```\ntool [options] command\n```\n\n" +
+		"## Options\n\n" +
+		"| Option | Argument | Default | Environment | Description |\n" +
+		"|---|---|---|---|---|\n" +
+		"| `-h`, `--help` |  |  |  | Print help |\n" +
+		"| `-V`, `--version` |  |  |  | Print version |\n" +
+		"| `-f`, `--format` | json\\|yaml | json | TOOL_FORMAT | Output format |\n" +
+		"| `-O`, `--level` | [int] |  |  | Optimization level |\n" +
+		"\n## Commands\n\n" +
+		"| Command | Description |\n" +
+		"|---|---|\n" +
+		"| [`deploy`](tool-deploy.md) | Deploy application |\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteMarkdown() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGetOpt_WriteHTML(t *testing.T) {
+	var buf strings.Builder
+	opts := docFixture()
+	if err := opts.commands[0].WriteHTML(&buf); err != nil {
+		t.Fatalf("WriteHTML() unexpected error %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"<title>tool deploy</title>\n",
+		"<p>Deploy application</p>\n",
+		"<pre>tool deploy [options] APP</pre>\n",
+		"<h2>Global options</h2>\n",
+		"<tr><td><code>-c, --config</code></td><td>string</td><td></td><td></td><td>required, Config file</td></tr>\n",
+		"<tr><td><code>-f, --format</code></td><td>json|yaml</td><td>json</td><td>TOOL_FORMAT</td><td>Output format</td></tr>\n",
+		"<h2>Arguments</h2>\n",
+		"<tr><th>Argument</th><th>Environment</th><th>Description</th></tr>\n",
+		"<tr><td><code>APP</code></td><td>TOOL_APP</td><td>Application</td></tr>\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteHTML() missing %q in\n%s", want, got)
+		}
+	}
+	buf.Reset()
+	_ = opts.WriteHTML(&buf)
+	if got := buf.String(); !strings.Contains(got, "<p>Manages &lt;deployments&gt;.</p>") || !strings.Contains(got, `<a href="tool-deploy.html"><code>deploy</code></a>`) {
+		t.Errorf("WriteHTML() = %s", got)
+	}
+}
+
+func TestGetOpt_WriteDocPages(t *testing.T) {
+	dir := t.TempDir()
+	if err := docFixture().WriteDocPages(dir, "md"); err != nil {
+		t.Fatalf("WriteDocPages() unexpected error %v", err)
+	}
+	for _, page := range []string{"tool.md", "tool-deploy.md"} {
+		if _, err := os.Stat(filepath.Join(dir, page)); err != nil {
+			t.Errorf("WriteDocPages() did not write %v", page)
+		}
+	}
+	if err := docFixture().WriteDocPages(dir, "pdf"); err == nil {
+		t.Errorf("WriteDocPages(pdf) expected to fail")
+	}
+}
+
+func TestGetOpt_WriteDocError(t *testing.T) {
+	opts := docFixture()
+	if err := opts.WriteMarkdown(failingWriter{}); err == nil {
+		t.Errorf("WriteMarkdown() expected to fail")
+	}
+	if err := opts.WriteHTML(failingWriter{}); err == nil {
+		t.Errorf("WriteHTML() expected to fail")
+	}
+	if err := opts.WriteDocPages(filepath.Join(t.TempDir(), "missing"), "md"); err == nil {
+		t.Errorf("WriteDocPages() expected to fail for missing directory")
+	}
+}
@@ -0,0 +1,115 @@
+package getopt
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func (opts *GetOpt) Env(name string, vars ...string) error {
+	if len(vars) == 0 {
+		return errors.New("No environment variable given for " + name)
+	}
+	if item, found := opts.optionMap[name]; found {
+		item.env = append(item.env, vars...)
+		return nil
+	}
+	for _, pos := range opts.positionals {
+		if pos.name == name {
+			pos.env = append(pos.env, vars...)
+			return nil
+		}
+	}
+	return errors.New("Unknown option or argument `" + name + "`")
+}
+
+func (opts *GetOpt) applyEnv(opt *optDef) error {
+	if opt.count > 0 {
+		return nil
+	}
+	name, value, found := lookupEnv(opt.env)
+	if !found {
+		return nil
+	}
+	var err error
+	switch {
+	case opt.noArg && opt.multiple:
+		var times int
+		if times, err = strconv.Atoi(value); err == nil {
+			for ; times > 0 && err == nil; times-- {
+				err = opt.argConv("")
+			}
+		}
+	case opt.noArg:
+		var set bool
+		if set, err = strconv.ParseBool(value); err == nil && set {
+			err = opt.argConv("")
+		} else if err == nil && opt.argNegate != nil {
+			err = opt.argNegate()
+		}
+	case opt.list:
+		if opt.argReset != nil {
+			opt.argReset()
+		}
+		for _, item := range envList(value) {
+			if err = opt.argConv(item); err != nil {
+				break
+			}
+		}
+	default:
+		err = opt.argConv(value)
+	}
+	if err != nil {
+		return envError(name, err)
+	}
+	opt.count++
+	return nil
+}
+
+func (opts *GetOpt) applyPositionalEnv(pos *posDef) error {
+	if pos.count > 0 {
+		return nil
+	}
+	name, value, found := lookupEnv(pos.env)
+	if !found {
+		return nil
+	}
+	values := []string{value}
+	if pos.variadic {
+		values = envList(value)
+	}
+	if pos.max > 0 && len(values) > pos.max {
+		return envError(name, errors.New("At most "+strconv.Itoa(pos.max)+" arguments allowed for "+pos.name))
+	}
+	if pos.variadic && pos.argReset != nil {
+		pos.argReset()
+	}
+	for _, item := range values {
+		if err := pos.argConv(item); err != nil {
+			return envError(name, err)
+		}
+		pos.count++
+	}
+	return nil
+}
+
+func lookupEnv(names []string) (string, string, bool) {
+	for _, name := range names {
+		if value, found := os.LookupEnv(name); found {
+			return name, value, true
+		}
+	}
+	return "", "", false
+}
+
+func envList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func envError(name string, err error) error {
+	return errors.New("Invalid value of environment variable " + name + ": " + err.Error())
+}
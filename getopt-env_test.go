@@ -0,0 +1,197 @@
+package getopt
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetOpt_Env(t *testing.T) {
+	type test struct {
+		name        string
+		env         map[string]string
+		args        []string
+		wantHost    string
+		wantPort    int64
+		wantTags    []string
+		wantDebug   bool
+		wantParseOk bool
+	}
+	tests := []test{
+		{"nothing set", nil, []string{"prog"}, "", 80, []string{}, false, false},
+		{"required from env", map[string]string{"APP_HOST": "db"}, []string{"prog"}, "db", 80, []string{}, false, true},
+		{"fallback variable", map[string]string{"HOST": "db"}, []string{"prog"}, "db", 80, []string{}, false, true},
+		{"first variable wins", map[string]string{"APP_HOST": "a", "HOST": "b"}, []string{"prog"}, "a", 80, []string{}, false, true},
+		{"command line wins", map[string]string{"APP_HOST": "db", "APP_PORT": "8080"}, []string{"prog", "-H", "web", "-p1"}, "web", 1, []string{}, false, true},
+		{"default replaced", map[string]string{"APP_HOST": "db", "APP_PORT": "8080"}, []string{"prog"}, "db", 8080, []string{}, false, true},
+		{"list split", map[string]string{"APP_HOST": "db", "APP_TAGS": "a,b"}, []string{"prog"}, "db", 80, []string{"a", "b"}, false, true},
+		{"empty list", map[string]string{"APP_HOST": "db", "APP_TAGS": ""}, []string{"prog"}, "db", 80, []string{}, false, true},
+		{"flag", map[string]string{"APP_HOST": "db", "APP_DEBUG": "true"}, []string{"prog"}, "db", 80, []string{}, true, true},
+		{"invalid number", map[string]string{"APP_HOST": "db", "APP_PORT": "http"}, []string{"prog"}, "db", 80, []string{}, false, false},
+		{"invalid flag", map[string]string{"APP_HOST": "db", "APP_DEBUG": "maybe"}, []string{"prog"}, "db", 80, []string{}, false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, name := range []string{"APP_HOST", "HOST", "APP_PORT", "APP_TAGS", "APP_DEBUG"} {
+				if value, found := test.env[name]; found {
+					t.Setenv(name, value)
+				} else {
+					t.Setenv(name, "")
+					os.Unsetenv(name)
+				}
+			}
+			opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+				return true, err
+			})
+			host, _ := opts.StringValue('H', "--host", true, "host")
+			port, _ := opts.IntDefault('p', "--port", 80, "port")
+			tags, _ := opts.StringList('t', "--tag", "tags")
+			debug, _ := opts.Flag('d', "--debug", "debug")
+			for _, err := range []error{
+				opts.Env("--host", "APP_HOST", "HOST"),
+				opts.Env("--port", "APP_PORT"),
+				opts.Env("--tag", "APP_TAGS"),
+				opts.Env("--debug", "APP_DEBUG"),
+			} {
+				if err != nil {
+					t.Fatalf("Unexpected error %v on setup", err)
+				}
+			}
+			if _, err := opts.Parse(test.args, false); (err == nil) != test.wantParseOk {
+				t.Errorf("Parse() error = %v, want ok = %v", err, test.wantParseOk)
+			} else if err == nil && (*host != test.wantHost || *port != test.wantPort || !reflect.DeepEqual(*tags, test.wantTags) || *debug != test.wantDebug) {
+				t.Errorf("Unexpected values: %v, %v, %v, %v", *host, *port, *tags, *debug)
+			}
+		})
+	}
+}
+
+func TestGetOpt_EnvFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       string
+		args      []string
+		wantColor bool
+		wantLevel int
+	}{
+		{"defaults", "", []string{"prog"}, true, 0},
+		{"negated by env", "false", []string{"prog"}, false, 0},
+		{"command line wins", "false", []string{"prog", "--color=true"}, true, 0},
+		{"counter", "3", []string{"prog"}, true, 3},
+		{"counter from command line", "3", []string{"prog", "-v"}, true, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("APP_COLOR", test.env)
+			t.Setenv("APP_VERBOSE", test.env)
+			if test.env == "" {
+				os.Unsetenv("APP_COLOR")
+				os.Unsetenv("APP_VERBOSE")
+			} else if test.wantLevel == 0 {
+				os.Unsetenv("APP_VERBOSE")
+			} else {
+				os.Unsetenv("APP_COLOR")
+			}
+			opts := New()
+			color, _ := opts.BoolDefault('c', "--color", true, "color")
+			level, _ := opts.Counter('v', "--verbose", 0, "verbosity")
+			_ = opts.Negatable("--color")
+			_ = opts.Env("--color", "APP_COLOR")
+			_ = opts.Env("--verbose", "APP_VERBOSE")
+			if _, err := opts.Parse(test.args, false); err != nil {
+				t.Fatalf("Unexpected error %v on parse", err)
+			}
+			if *color != test.wantColor || *level != test.wantLevel {
+				t.Errorf("Unexpected values: %v, %v", *color, *level)
+			}
+		})
+	}
+}
+
+func TestGetOpt_EnvSetup(t *testing.T) {
+	opts := New()
+	_, _ = opts.StringValue('o', "--output", false, "output file")
+	if err := opts.Env("--input", "APP_INPUT"); err == nil {
+		t.Errorf("Env() expected to fail for unknown option")
+	}
+	if err := opts.Env("--output"); err == nil {
+		t.Errorf("Env() expected to fail without variables")
+	}
+	if err := opts.Env("-o", "APP_OUTPUT", "OUTPUT"); err != nil {
+		t.Errorf("Unexpected error %v on setup", err)
+	}
+	if help := opts.HelpString(); !strings.Contains(help, "[env: APP_OUTPUT, OUTPUT]") {
+		t.Errorf("Help() does not show environment variables:\n%s", help)
+	}
+}
+
+func TestGetOpt_MarshalEnv(t *testing.T) {
+	type target struct {
+		Output string            `flag:"o,output" env:"APP_OUTPUT"`
+		Tags   []string          `flag:"t,tag" env:"APP_TAGS"`
+		Labels map[string]string `flag:"l,label" env:"APP_LABELS"`
+		Name   string            `arg:"0" env:"APP_NAME"`
+		Files  []string          `args:"rest" min:"1" env:"APP_FILES"`
+	}
+	t.Setenv("APP_OUTPUT", "out.txt")
+	t.Setenv("APP_TAGS", "a,b")
+	t.Setenv("APP_LABELS", "x:1,y:2")
+	t.Setenv("APP_NAME", "env")
+	t.Setenv("APP_FILES", "f1,f2")
+	tests := []struct {
+		name string
+		args []string
+		want target
+	}{
+		{"environment", []string{"prog"}, target{"out.txt", []string{"a", "b"}, map[string]string{"x": "1", "y": "2"}, "env", []string{"f1", "f2"}}},
+		{"command line wins", []string{"prog", "-t", "c", "-l", "z:3", "cli", "f3"}, target{"out.txt", []string{"c"}, map[string]string{"z": "3"}, "cli", []string{"f3"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value := target{}
+			if _, err := New().Marshal(&value, test.args, false); err != nil {
+				t.Fatalf("Unexpected error %v on parse", err)
+			}
+			if !reflect.DeepEqual(value, test.want) {
+				t.Errorf("Unexpected values: %+v", value)
+			}
+		})
+	}
+}
+
+func TestGetOpt_MarshalEnvReplacesDefault(t *testing.T) {
+	type target struct {
+		List   []string       `flag:"l,list" default:"a" env:"ZZ_LIST"`
+		Limits map[string]int `flag:"m,limit" default:"cpu:1" env:"ZZ_LIMITS"`
+		Files  []string       `args:"rest" default:"f0" env:"ZZ_FILES"`
+	}
+	t.Setenv("ZZ_LIST", "b,c")
+	t.Setenv("ZZ_LIMITS", "mem:2")
+	t.Setenv("ZZ_FILES", "f1,f2")
+	value := target{}
+	if _, err := New().Marshal(&value, []string{"prog"}, false); err != nil {
+		t.Fatalf("Unexpected error %v on parse", err)
+	}
+	want := target{[]string{"b", "c"}, map[string]int{"mem": 2}, []string{"f1", "f2"}}
+	if !reflect.DeepEqual(value, want) {
+		t.Errorf("Unexpected values: %+v", value)
+	}
+}
+
+func TestGetOpt_PositionalEnv(t *testing.T) {
+	t.Setenv("APP_HOST", "db")
+	opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+		return true, err
+	})
+	host, _ := opts.StringPositional("HOST", true, "host")
+	if err := opts.Env("HOST", "APP_HOST"); err != nil {
+		t.Fatalf("Unexpected error %v on setup", err)
+	}
+	if _, err := opts.Parse([]string{"prog"}, false); err != nil || *host != "db" {
+		t.Errorf("Parse() = %v, error = %v", *host, err)
+	}
+	if help := opts.HelpString(); !strings.Contains(help, "host [env: APP_HOST]") {
+		t.Errorf("Help() does not show environment variables:\n%s", help)
+	}
+}
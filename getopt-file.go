@@ -0,0 +1,268 @@
+package getopt
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+)
+
+type InputFile struct {
+	name string
+	file *os.File
+}
+
+func (f *InputFile) Name() string {
+	return f.name
+}
+
+func (f *InputFile) Open() (*os.File, error) {
+	if f.file == nil {
+		if f.name == "" {
+			return nil, errors.New("No input file specified")
+		} else if f.name == "-" {
+			f.file = os.Stdin
+		} else if file, err := os.Open(f.name); err != nil {
+			return nil, err
+		} else {
+			f.file = file
+		}
+	}
+	return f.file, nil
+}
+
+func (f *InputFile) Read(p []byte) (int, error) {
+	if file, err := f.Open(); err != nil {
+		return 0, err
+	} else {
+		return file.Read(p)
+	}
+}
+
+func (f *InputFile) Close() error {
+	file := f.file
+	f.file = nil
+	if file == nil || file == os.Stdin {
+		return nil
+	}
+	return file.Close()
+}
+
+type OutputFile struct {
+	name string
+	file *os.File
+}
+
+func (f *OutputFile) Name() string {
+	return f.name
+}
+
+func (f *OutputFile) Open() (*os.File, error) {
+	if f.file == nil {
+		if f.name == "" {
+			return nil, errors.New("No output file specified")
+		} else if f.name == "-" {
+			f.file = os.Stdout
+		} else if file, err := os.Create(f.name); err != nil {
+			return nil, err
+		} else {
+			f.file = file
+		}
+	}
+	return f.file, nil
+}
+
+func (f *OutputFile) Write(p []byte) (int, error) {
+	if file, err := f.Open(); err != nil {
+		return 0, err
+	} else {
+		return file.Write(p)
+	}
+}
+
+func (f *OutputFile) Close() error {
+	file := f.file
+	f.file = nil
+	if file == nil || file == os.Stdout {
+		return nil
+	}
+	return file.Close()
+}
+
+type stdFile struct {
+	*os.File
+}
+
+func (f stdFile) Close() error {
+	if f.File == os.Stdin || f.File == os.Stdout {
+		return nil
+	}
+	return f.File.Close()
+}
+
+func (opts *GetOpt) Close() error {
+	errs := make([]error, 0)
+	for _, closer := range opts.closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	opts.closers = nil
+	for _, cmd := range opts.commands {
+		errs = append(errs, cmd.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (opts *GetOpt) InputFileValue(flag rune, longFlag string, required bool, help string) (*InputFile, error) {
+	return opts.InputFileValueV([]rune{flag}, []string{longFlag}, required, help)
+}
+
+func (opts *GetOpt) InputFileValueV(flags []rune, longFlags []string, required bool, help string) (*InputFile, error) {
+	return opts.inputFile(flags, longFlags, "", required, help)
+}
+
+func (opts *GetOpt) InputFileDefault(flag rune, longFlag string, value string, help string) (*InputFile, error) {
+	return opts.InputFileDefaultV([]rune{flag}, []string{longFlag}, value, help)
+}
+
+func (opts *GetOpt) InputFileDefaultV(flags []rune, longFlags []string, value string, help string) (*InputFile, error) {
+	return opts.inputFile(flags, longFlags, value, false, help)
+}
+
+func (opts *GetOpt) inputFile(flags []rune, longFlags []string, value string, required bool, help string) (*InputFile, error) {
+	result := &InputFile{name: value}
+	def := optDef{
+		posixOpts: flags,
+		longOpts:  longFlags,
+		help:      help,
+		required:  required,
+		defValue:  value,
+		argType:   "file",
+	}
+	def.argConv = func(arg string) error {
+		err := result.Close()
+		result.name = arg
+		return err
+	}
+	def.argReset = func() {
+		_ = result.Close()
+		result.name = value
+	}
+	opts.closers = append(opts.closers, result)
+	return result, opts.safeAdd(def)
+}
+
+func (opts *GetOpt) OutputFileValue(flag rune, longFlag string, required bool, help string) (*OutputFile, error) {
+	return opts.OutputFileValueV([]rune{flag}, []string{longFlag}, required, help)
+}
+
+func (opts *GetOpt) OutputFileValueV(flags []rune, longFlags []string, required bool, help string) (*OutputFile, error) {
+	return opts.outputFile(flags, longFlags, "", required, help)
+}
+
+func (opts *GetOpt) OutputFileDefault(flag rune, longFlag string, value string, help string) (*OutputFile, error) {
+	return opts.OutputFileDefaultV([]rune{flag}, []string{longFlag}, value, help)
+}
+
+func (opts *GetOpt) OutputFileDefaultV(flags []rune, longFlags []string, value string, help string) (*OutputFile, error) {
+	return opts.outputFile(flags, longFlags, value, false, help)
+}
+
+func (opts *GetOpt) outputFile(flags []rune, longFlags []string, value string, required bool, help string) (*OutputFile, error) {
+	result := &OutputFile{name: value}
+	def := optDef{
+		posixOpts: flags,
+		longOpts:  longFlags,
+		help:      help,
+		required:  required,
+		defValue:  value,
+		argType:   "file",
+	}
+	def.argConv = func(arg string) error {
+		err := result.Close()
+		result.name = arg
+		return err
+	}
+	def.argReset = func() {
+		_ = result.Close()
+		result.name = value
+	}
+	opts.closers = append(opts.closers, result)
+	return result, opts.safeAdd(def)
+}
+
+var (
+	readerType      = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	readCloserType  = reflect.TypeOf((*io.ReadCloser)(nil)).Elem()
+	writerType      = reflect.TypeOf((*io.Writer)(nil)).Elem()
+	writeCloserType = reflect.TypeOf((*io.WriteCloser)(nil)).Elem()
+	inputFileType   = reflect.TypeOf((*InputFile)(nil))
+	outputFileType  = reflect.TypeOf((*OutputFile)(nil))
+	osFileType      = reflect.TypeOf((*os.File)(nil))
+)
+
+func (opts *GetOpt) fileCallback(fieldType reflect.StructField, fieldValue reflect.Value) func(string) error {
+	switch fieldType.Type {
+	case readerType, readCloserType, inputFileType:
+		return func(strval string) error {
+			file := &InputFile{name: strval}
+			opts.closers = append(opts.closers, file)
+			fieldValue.Set(reflect.ValueOf(file))
+			return nil
+		}
+	case writerType, writeCloserType, outputFileType:
+		return func(strval string) error {
+			file := &OutputFile{name: strval}
+			opts.closers = append(opts.closers, file)
+			fieldValue.Set(reflect.ValueOf(file))
+			return nil
+		}
+	case osFileType:
+		output := fieldType.Tag.Get("file") == "w"
+		name := ""
+		opts.deferOpen(func() error {
+			var file *os.File
+			var err error
+			if name == "" {
+				return nil
+			} else if name == "-" && output {
+				file = os.Stdout
+			} else if name == "-" {
+				file = os.Stdin
+			} else if output {
+				file, err = os.Create(name)
+			} else {
+				file, err = os.Open(name)
+			}
+			name = ""
+			if err == nil {
+				opts.closers = append(opts.closers, stdFile{file})
+				fieldValue.Set(reflect.ValueOf(file))
+			}
+			return err
+		})
+		return func(strval string) error {
+			name = strval
+			return nil
+		}
+	}
+	return nil
+}
+
+func (opts *GetOpt) deferOpen(open func() error) {
+	root := opts
+	for root.parent != nil {
+		root = root.parent
+	}
+	root.opens = append(root.opens, open)
+}
+
+func (opts *GetOpt) openFiles() error {
+	for _, open := range opts.opens {
+		if err := open(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package getopt
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetOpt_InputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := New()
+	input, _ := opts.InputFileDefault('i', "--input", "-", "input")
+	other, _ := opts.InputFileValue('f', "--file", false, "file")
+	if _, err := opts.Parse([]string{"prog", "-i", path}, true); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if input.Name() != path {
+		t.Errorf("Name() = %v, want %v", input.Name(), path)
+	}
+	if data, err := io.ReadAll(input); err != nil || string(data) != "content" {
+		t.Errorf("ReadAll() = %v, %v", string(data), err)
+	}
+	if _, err := other.Open(); err == nil {
+		t.Errorf("Open() expected to fail for missing file name")
+	}
+	if err := opts.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	opts.ResetValues()
+	if file, err := input.Open(); err != nil || file != os.Stdin {
+		t.Errorf("Open() = %v, %v, want stdin", file, err)
+	}
+}
+
+func TestGetOpt_OutputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "output.txt")
+	opts := New()
+	output, _ := opts.OutputFileDefault('o', "--output", "-", "output")
+	if _, err := opts.Parse([]string{"prog", "--output", path}, true); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("output file is expected to be created lazily")
+	}
+	if _, err := io.WriteString(output, "content"); err != nil {
+		t.Errorf("Write() error = %v", err)
+	}
+	if err := opts.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "content" {
+		t.Errorf("ReadFile() = %v, %v", string(data), err)
+	}
+}
+
+func TestGetOpt_MarshalFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	target := struct {
+		Input  io.Reader      `flag:"i,input" default:"-"`
+		Output io.WriteCloser `flag:"o,output" default:"-"`
+		File   *os.File       `flag:"f,file"`
+		Log    *os.File       `flag:"l,log" file:"w"`
+	}{}
+	opts := New()
+	defer opts.Close()
+	if _, err := opts.Marshal(&target, []string{"prog", "-f", path, "--log=-", "-"}, true); err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if input, ok := target.Input.(*InputFile); !ok || input.Name() != "-" {
+		t.Errorf("Input = %v", target.Input)
+	}
+	if output, ok := target.Output.(*OutputFile); !ok || output.Name() != "-" {
+		t.Errorf("Output = %v", target.Output)
+	}
+	if target.Log != os.Stdout {
+		t.Errorf("Log = %v, want stdout", target.Log)
+	}
+	if data, err := io.ReadAll(target.File); err != nil || string(data) != "content" {
+		t.Errorf("ReadAll() = %v, %v", string(data), err)
+	}
+}
+
+func TestGetOpt_MarshalFilesOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.txt")
+	other := filepath.Join(dir, "other.txt")
+	type target struct {
+		Log *os.File `flag:"l,log" file:"w"`
+	}
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"parse error", []string{"prog", "--log", path, "--unknown"}},
+		{"help", []string{"prog", "--log", path, "--help"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			opts := New().WithDefaults("prog", "1.0").WithOutput(io.Discard, io.Discard)
+			defer opts.Close()
+			_, _ = opts.Marshal(&target{}, test.args, false)
+			if data, err := os.ReadFile(path); err != nil || string(data) != "content" {
+				t.Errorf("ReadFile() = %q, %v", string(data), err)
+			}
+		})
+	}
+	value := target{}
+	opts := New()
+	if _, err := opts.Marshal(&value, []string{"prog", "--log", other, "--log", path}, false); err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if value.Log == nil || value.Log.Name() != path || len(opts.closers) != 1 {
+		t.Errorf("Log = %v, closers = %d", value.Log, len(opts.closers))
+	}
+	if _, err := os.Stat(other); !os.IsNotExist(err) {
+		t.Errorf("replaced log file is not expected to be created")
+	}
+	if err := opts.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestGetOpt_CloseCommands(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	type deploy struct {
+		File *os.File `flag:"f,file"`
+	}
+	target := struct {
+		Deploy deploy `cmd:"deploy"`
+	}{}
+	opts := New()
+	input, _ := opts.Command("status", "Show status", nil).InputFileValue('i', "--input", false, "input")
+	if _, err := opts.Marshal(&target, []string{"prog", "deploy", "-f", path}, false); err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	input.name = path
+	if _, err := input.Open(); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if err := opts.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := target.Deploy.File.Read(make([]byte, 1)); err == nil {
+		t.Errorf("command file is expected to be closed")
+	}
+	if input.file != nil {
+		t.Errorf("command input file is expected to be closed")
+	}
+}
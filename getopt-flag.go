@@ -14,7 +14,10 @@ func (opts *GetOpt) FlagV(flags []rune, longFlags []string, help string) (*bool,
 	}
 	def.argConv = func(arg string) error {
 		result = true
-		def.count++
+		return nil
+	}
+	def.argNegate = func() error {
+		result = false
 		return nil
 	}
 	def.argReset = func() {
@@ -10,6 +10,7 @@ func TestGetOpt_Flag(t *testing.T) {
 		init        func(getopt *GetOpt) (*bool, error)
 		args        []string
 		posix       bool
+		abbreviate  bool
 		wantSetupOk bool
 		wantValue   bool
 		wantParseOk bool
@@ -48,10 +49,44 @@ func TestGetOpt_Flag(t *testing.T) {
 			wantValue:   true,
 			wantParseOk: true,
 		},
+		{
+			name: "abbreviated longflag",
+			init: func(getopt *GetOpt) (*bool, error) {
+				return getopt.Flag('b', "--bool", "help")
+			},
+			args:        []string{"prog", "--bo"},
+			posix:       true,
+			abbreviate:  true,
+			wantSetupOk: true,
+			wantValue:   true,
+			wantParseOk: true,
+		},
+		{
+			name: "abbreviated longflag is unknown by default",
+			init: func(getopt *GetOpt) (*bool, error) {
+				return getopt.Flag('b', "--bool", "help")
+			},
+			args:        []string{"prog", "--bo"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   false,
+			wantParseOk: false,
+		},
+		{
+			name: "unknown flag fails even if followed by known one",
+			init: func(getopt *GetOpt) (*bool, error) {
+				return getopt.Flag('b', "--bool", "help")
+			},
+			args:        []string{"prog", "-q", "-b"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   true,
+			wantParseOk: false,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			getopt := New()
+			getopt := New().WithAbbreviations(test.abbreviate)
 			if result, err := test.init(getopt); err != nil {
 				if test.wantSetupOk {
 					t.Errorf("Unexpected error %n on setup", err)
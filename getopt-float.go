@@ -18,7 +18,6 @@ func (opts *GetOpt) FloatValueV(flags []rune, longFlags []string, required bool,
 	def.argConv = func(arg string) error {
 		var err error
 		result, err = strconv.ParseFloat(arg, 64)
-		def.count++
 		return err
 	}
 	def.argReset = func() {
@@ -36,12 +35,38 @@ func (opts *GetOpt) FloatDefaultV(flags []rune, longFlags []string, value float6
 		posixOpts: flags,
 		longOpts:  longFlags,
 		help:      help,
+		defValue:  strconv.FormatFloat(value, 'g', -1, 64),
 		argType:   "float",
 	}
 	def.argConv = func(arg string) error {
 		var err error
 		result, err = strconv.ParseFloat(arg, 64)
-		def.count++
+		return err
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.safeAdd(def)
+}
+
+func (opts *GetOpt) FloatOptional(flag rune, longFlag string, value float64, implicit float64, help string) (*float64, error) {
+	return opts.FloatOptionalV([]rune{flag}, []string{longFlag}, value, implicit, help)
+}
+
+func (opts *GetOpt) FloatOptionalV(flags []rune, longFlags []string, value float64, implicit float64, help string) (*float64, error) {
+	result := value
+	def := optDef{
+		posixOpts:   flags,
+		longOpts:    longFlags,
+		help:        help,
+		defValue:    strconv.FormatFloat(value, 'g', -1, 64),
+		optionalArg: true,
+		implicit:    strconv.FormatFloat(implicit, 'g', -1, 64),
+		argType:     "float",
+	}
+	def.argConv = func(arg string) error {
+		var err error
+		result, err = strconv.ParseFloat(arg, 64)
 		return err
 	}
 	def.argReset = func() {
@@ -60,12 +85,13 @@ func (opts *GetOpt) FloatListV(flags []rune, longFlags []string, help string) (*
 		posixOpts: flags,
 		longOpts:  longFlags,
 		help:      help,
+		multiple:  true,
+		list:      true,
 		argType:   "float",
 	}
 	def.argConv = func(arg string) error {
 		if value, err := strconv.ParseFloat(arg, 64); err == nil {
 			result = append(result, value)
-			def.count++
 			return nil
 		} else {
 			return err
@@ -76,3 +102,41 @@ func (opts *GetOpt) FloatListV(flags []rune, longFlags []string, help string) (*
 	}
 	return &result, opts.safeAdd(def)
 }
+
+func (opts *GetOpt) FloatPositional(name string, required bool, help string) (*float64, error) {
+	return opts.floatPositional(name, required, 0, help)
+}
+
+func (opts *GetOpt) FloatPositionalDefault(name string, value float64, help string) (*float64, error) {
+	return opts.floatPositional(name, false, value, help)
+}
+
+func (opts *GetOpt) floatPositional(name string, required bool, value float64, help string) (*float64, error) {
+	result := value
+	def := positional(name, required, help)
+	def.argConv = func(arg string) error {
+		var err error
+		result, err = strconv.ParseFloat(arg, 64)
+		return err
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.addPositional(def)
+}
+
+func (opts *GetOpt) FloatPositionalList(name string, min int, max int, help string) (*[]float64, error) {
+	result := make([]float64, 0)
+	def := positionalList(name, min, max, help)
+	def.argConv = func(arg string) error {
+		value, err := strconv.ParseFloat(arg, 64)
+		if err == nil {
+			result = append(result, value)
+		}
+		return err
+	}
+	def.argReset = func() {
+		result = make([]float64, 0)
+	}
+	return &result, opts.addPositional(def)
+}
@@ -115,6 +115,61 @@ func TestGetOpt_Float(t *testing.T) {
 			wantValue:   1.61,
 			wantParseOk: true,
 		},
+		{
+			name: "optional argument absent",
+			init: func(getopt *GetOpt) (*float64, error) {
+				return getopt.FloatOptional('f', "--float", 1.5, 2.5, "help")
+			},
+			args:        []string{"prog"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   1.5,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument implicit",
+			init: func(getopt *GetOpt) (*float64, error) {
+				return getopt.FloatOptional('f', "--float", 1.5, 2.5, "help")
+			},
+			args:        []string{"prog", "-f"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   2.5,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument inline",
+			init: func(getopt *GetOpt) (*float64, error) {
+				return getopt.FloatOptional('f', "--float", 1.5, 2.5, "help")
+			},
+			args:        []string{"prog", "-f10.5"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   10.5,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument longopt inline",
+			init: func(getopt *GetOpt) (*float64, error) {
+				return getopt.FloatOptional('f', "--float", 1.5, 2.5, "help")
+			},
+			args:        []string{"prog", "--float=10.5"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   10.5,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument not taken from next word",
+			init: func(getopt *GetOpt) (*float64, error) {
+				return getopt.FloatOptional('f', "--float", 1.5, 2.5, "help")
+			},
+			args:        []string{"prog", "--float", "10.5"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   2.5,
+			wantParseOk: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
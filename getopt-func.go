@@ -15,6 +15,23 @@ func (opts *GetOpt) ArgFuncV(flags []rune, longFlags []string, action func(strin
 	return opts.safeAdd(def)
 }
 
+func (opts *GetOpt) ArgFuncOptional(flag rune, longFlag string, implicit string, action func(string) error, help string) error {
+	return opts.ArgFuncOptionalV([]rune{flag}, []string{longFlag}, implicit, action, help)
+}
+
+func (opts *GetOpt) ArgFuncOptionalV(flags []rune, longFlags []string, implicit string, action func(string) error, help string) error {
+	def := optDef{
+		posixOpts:   flags,
+		longOpts:    longFlags,
+		help:        help,
+		optionalArg: true,
+		implicit:    implicit,
+		argType:     "value",
+	}
+	def.argConv = action
+	return opts.safeAdd(def)
+}
+
 func (opts *GetOpt) FlagFunc(flag rune, longFlag string, action func() error, help string) error {
 	return opts.FlagFuncV([]rune{flag}, []string{longFlag}, action, help)
 }
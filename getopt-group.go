@@ -0,0 +1,168 @@
+package getopt
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+const (
+	groupExclusive = iota
+	groupAtLeastOne
+	groupAllOrNone
+	groupRequires
+	groupConflicts
+)
+
+type optGroup struct {
+	kind    int
+	option  string
+	options []string
+}
+
+func (group optGroup) String() string {
+	list := strings.Join(group.options, ", ")
+	switch group.kind {
+	case groupExclusive:
+		return "Options " + list + " are mutually exclusive"
+	case groupAtLeastOne:
+		return "At least one of " + list + " is required"
+	case groupAllOrNone:
+		return "Options " + list + " must be used together"
+	case groupRequires:
+		return "Option " + group.option + " requires " + list
+	default:
+		return "Option " + group.option + " conflicts with " + list
+	}
+}
+
+func (group optGroup) subject() string {
+	if group.option != "" {
+		return group.option
+	}
+	return group.options[0]
+}
+
+func (opts *GetOpt) Exclusive(options ...string) error {
+	return opts.addGroup(optGroup{kind: groupExclusive, options: options})
+}
+
+func (opts *GetOpt) AtLeastOne(options ...string) error {
+	return opts.addGroup(optGroup{kind: groupAtLeastOne, options: options})
+}
+
+func (opts *GetOpt) AllOrNone(options ...string) error {
+	return opts.addGroup(optGroup{kind: groupAllOrNone, options: options})
+}
+
+func (opts *GetOpt) Requires(option string, required ...string) error {
+	return opts.addGroup(optGroup{kind: groupRequires, option: option, options: required})
+}
+
+func (opts *GetOpt) Conflicts(option string, conflicting ...string) error {
+	return opts.addGroup(optGroup{kind: groupConflicts, option: option, options: conflicting})
+}
+
+func (opts *GetOpt) addGroup(group optGroup) error {
+	if len(group.options) == 0 || (group.option == "" && len(group.options) < 2) {
+		return errors.New("Not enough options for constraint: " + group.String())
+	}
+	for _, option := range append([]string{group.option}, group.options...) {
+		if _, found := opts.lookup(option); option != "" && !found {
+			return errors.New("Unknown option `" + option + "` in constraint: " + group.String())
+		}
+	}
+	opts.groups = append(opts.groups, group)
+	return nil
+}
+
+func (opts *GetOpt) checkGroup(group optGroup) error {
+	given := 0
+	for _, option := range group.options {
+		if opts.given(option) {
+			given++
+		}
+	}
+	violated := false
+	switch group.kind {
+	case groupExclusive:
+		violated = given > 1
+	case groupAtLeastOne:
+		violated = given == 0
+	case groupAllOrNone:
+		violated = given > 0 && given < len(group.options)
+	case groupRequires:
+		violated = opts.given(group.option) && given < len(group.options)
+	case groupConflicts:
+		violated = opts.given(group.option) && given > 0
+	}
+	if violated {
+		return errors.New(group.String())
+	}
+	return nil
+}
+
+func (opts *GetOpt) given(option string) bool {
+	item, found := opts.lookup(option)
+	return found && item.count > 0
+}
+
+var groupTags = []struct {
+	tag      string
+	kind     int
+	relation bool
+}{
+	{"exclusive", groupExclusive, false},
+	{"atleastone", groupAtLeastOne, false},
+	{"allornone", groupAllOrNone, false},
+	{"requires", groupRequires, true},
+	{"conflicts", groupConflicts, true},
+}
+
+type groupCollector struct {
+	named  map[string]*optGroup
+	groups []*optGroup
+}
+
+func (collector *groupCollector) collect(tag reflect.StructTag, key string) {
+	for _, groupTag := range groupTags {
+		if names, ok := tag.Lookup(groupTag.tag); !ok {
+			continue
+		} else if groupTag.relation {
+			group := &optGroup{kind: groupTag.kind, option: key, options: optionKeys(names)}
+			collector.groups = append(collector.groups, group)
+		} else {
+			for _, name := range strings.Split(names, ",") {
+				id := groupTag.tag + ":" + name
+				if group, found := collector.named[id]; found {
+					group.options = append(group.options, key)
+				} else {
+					group = &optGroup{kind: groupTag.kind, options: []string{key}}
+					collector.named[id] = group
+					collector.groups = append(collector.groups, group)
+				}
+			}
+		}
+	}
+}
+
+func (collector *groupCollector) register(opts *GetOpt) error {
+	for _, group := range collector.groups {
+		if err := opts.addGroup(*group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func optionKeys(names string) []string {
+	keys := make([]string, 0)
+	for _, name := range strings.Split(names, ",") {
+		if runes := []rune(name); len(runes) == 1 {
+			keys = append(keys, "-"+name)
+		} else if len(runes) > 1 {
+			keys = append(keys, "--"+name)
+		}
+	}
+	return keys
+}
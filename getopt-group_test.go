@@ -0,0 +1,114 @@
+package getopt
+
+import (
+	"io"
+	"testing"
+)
+
+func TestGetOpt_Groups(t *testing.T) {
+	type test struct {
+		name        string
+		args        []string
+		wantParseOk bool
+	}
+	tests := []test{
+		{"no options", []string{"prog", "--in=x"}, true},
+		{"exclusive one", []string{"prog", "--json", "--in=x"}, true},
+		{"exclusive both", []string{"prog", "--json", "--yaml", "--in=x"}, false},
+		{"at least one missing", []string{"prog"}, false},
+		{"at least one both", []string{"prog", "--in=x", "--stdin"}, true},
+		{"requires satisfied", []string{"prog", "--stdin", "-u", "me", "-p", "secret"}, true},
+		{"requires missing", []string{"prog", "--stdin", "-u", "me"}, false},
+		{"requires not triggered", []string{"prog", "--stdin", "-p", "secret"}, true},
+		{"conflicts", []string{"prog", "--stdin", "-q", "-v"}, false},
+		{"conflicts not triggered", []string{"prog", "--stdin", "-v"}, true},
+		{"all or none all", []string{"prog", "--stdin", "--cert=c", "--key=k"}, true},
+		{"all or none partial", []string{"prog", "--stdin", "--key=k"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+				return true, err
+			})
+			_, _ = opts.Flag('j', "--json", "json output")
+			_, _ = opts.Flag('y', "--yaml", "yaml output")
+			_, _ = opts.StringValue('i', "--in", false, "input")
+			_, _ = opts.Flag('s', "--stdin", "read stdin")
+			_, _ = opts.StringValue('u', "--user", false, "user")
+			_, _ = opts.StringValue('p', "--password", false, "password")
+			_, _ = opts.Flag('q', "--quiet", "quiet")
+			_, _ = opts.Flag('v', "--verbose", "verbose")
+			_, _ = opts.StringValue('c', "--cert", false, "certificate")
+			_, _ = opts.StringValue('k', "--key", false, "key")
+			for _, err := range []error{
+				opts.Exclusive("--json", "--yaml"),
+				opts.AtLeastOne("--in", "--stdin"),
+				opts.Requires("--user", "--password"),
+				opts.Conflicts("-q", "-v"),
+				opts.AllOrNone("--cert", "--key"),
+			} {
+				if err != nil {
+					t.Fatalf("Unexpected error %v on setup", err)
+				}
+			}
+			if _, err := opts.Parse(test.args, false); (err == nil) != test.wantParseOk {
+				t.Errorf("Parse() error = %v, want ok = %v", err, test.wantParseOk)
+			}
+		})
+	}
+}
+
+func TestGetOpt_GroupsHelp(t *testing.T) {
+	for _, args := range [][]string{{"prog", "--help"}, {"prog", "--completion=bash"}} {
+		opts := New().WithDefaults("prog", "1.0").WithOutput(io.Discard, io.Discard)
+		opts.AddCompletion()
+		_, _ = opts.Flag('j', "--json", "json output")
+		_, _ = opts.Flag('y', "--yaml", "yaml output")
+		_, _ = opts.StringValue('o', "--output", true, "output")
+		if err := opts.AtLeastOne("--json", "--yaml"); err != nil {
+			t.Fatalf("Unexpected error %v on setup", err)
+		}
+		if _, err := opts.Parse(args, false); err != nil || !opts.Done() {
+			t.Errorf("Parse(%q) error = %v, done = %v", args, err, opts.Done())
+		}
+	}
+}
+
+func TestGetOpt_GroupSetup(t *testing.T) {
+	opts := New()
+	_, _ = opts.Flag('j', "--json", "json output")
+	if err := opts.Exclusive("--json", "--yaml"); err == nil {
+		t.Errorf("Exclusive() expected to fail for unknown option")
+	}
+	if err := opts.Exclusive("--json"); err == nil {
+		t.Errorf("Exclusive() expected to fail for single option")
+	}
+}
+
+func TestGetOpt_MarshalGroups(t *testing.T) {
+	type target struct {
+		Json     bool   `flag:"j,json" exclusive:"format"`
+		Yaml     bool   `flag:"y,yaml" exclusive:"format"`
+		User     string `flag:"u,user" requires:"password"`
+		Password string `flag:"p,password"`
+		Quiet    bool   `flag:"q" conflicts:"v"`
+		Verbose  bool   `flag:"v"`
+	}
+	tests := []struct {
+		args        []string
+		wantParseOk bool
+	}{
+		{[]string{"prog", "-j", "-u", "me", "-p", "pw", "-v"}, true},
+		{[]string{"prog", "-j", "-y"}, false},
+		{[]string{"prog", "-u", "me"}, false},
+		{[]string{"prog", "-qv"}, false},
+	}
+	for _, test := range tests {
+		opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+			return true, err
+		})
+		if _, err := opts.Marshal(&target{}, test.args, false); (err == nil) != test.wantParseOk {
+			t.Errorf("Marshal(%v) error = %v, want ok = %v", test.args, err, test.wantParseOk)
+		}
+	}
+}
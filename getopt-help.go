@@ -0,0 +1,235 @@
+package getopt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	helpIndent       = 2
+	helpGap          = 2
+	helpTermWidth    = 30
+	helpMinDesc      = 20
+	helpDefaultWidth = 80
+)
+
+type helpRow struct {
+	term string
+	desc string
+}
+
+func (opts *GetOpt) SetHelpWidth(width int) {
+	opts.helpWidth = width
+}
+
+func (opts *GetOpt) WithHelpWidth(width int) *GetOpt {
+	opts.SetHelpWidth(width)
+	return opts
+}
+
+func (opts *GetOpt) Help() error {
+	opts.done = true
+	// Posix requires help to be printed to stdout,
+	// that makes total sense as help is the requested result
+	opts.printHelp(opts.output())
+	return nil
+}
+
+func (opts *GetOpt) HelpString() string {
+	var buf strings.Builder
+	opts.printHelp(&buf)
+	return buf.String()
+}
+
+func (opts *GetOpt) printHelp(w io.Writer) {
+	if len(opts.positionals) > 0 {
+		fmt.Fprintln(w, opts.Synopsis())
+	}
+	for _, desc := range opts.description {
+		fmt.Fprintln(w, desc)
+	}
+	if len(opts.description) == 0 && opts.parent != nil {
+		fmt.Fprintln(w, opts.fullName()+":", opts.commandHelp)
+	}
+	own := make([]helpRow, 0)
+	for _, opt := range opts.optionList {
+		if !opt.hidden {
+			own = append(own, optionRow(opt))
+		}
+	}
+	arguments := make([]helpRow, 0)
+	for _, pos := range opts.positionals {
+		arguments = append(arguments, helpRow{pos.String(), withEnv(pos.help, pos.env)})
+	}
+	global := make([]helpRow, 0)
+	for cmd := opts.parent; cmd != nil; cmd = cmd.parent {
+		for _, opt := range cmd.optionList {
+			if !opt.hidden && !opts.shadows(opt) {
+				global = append(global, optionRow(opt))
+			}
+		}
+	}
+	commands := make([]helpRow, 0)
+	for _, cmd := range opts.commands {
+		commands = append(commands, helpRow{cmd.name, cmd.commandHelp})
+	}
+	width := opts.terminalWidth()
+	column := helpColumn(own, arguments, global, commands)
+	printRows(w, own, column, width)
+	if len(arguments) > 0 {
+		fmt.Fprintln(w, "Arguments:")
+		printRows(w, arguments, column, width)
+	}
+	if len(global) > 0 {
+		fmt.Fprintln(w, "Global options:")
+		printRows(w, global, column, width)
+	}
+	for _, group := range opts.groups {
+		fmt.Fprintln(w, group)
+	}
+	if len(commands) > 0 {
+		fmt.Fprintln(w, "Commands:")
+		printRows(w, commands, column, width)
+	}
+}
+
+func (opts *GetOpt) terminalWidth() int {
+	for cmd := opts; cmd != nil; cmd = cmd.parent {
+		if cmd.helpWidth > 0 {
+			return cmd.helpWidth
+		}
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return helpDefaultWidth
+}
+
+func optionRow(opt *optDef) helpRow {
+	names, long := optionNames(opt)
+	prefix, arg, suffix := optionArg(opt, long)
+	term := strings.Join(names, ", ") + prefix + arg + suffix
+	for _, name := range negatedNames(opt) {
+		term += ", " + name
+	}
+	return helpRow{term, withEnv(optionDesc(opt), opt.env)}
+}
+
+func withEnv(desc string, env []string) string {
+	if len(env) == 0 {
+		return desc
+	}
+	return strings.TrimSpace(desc + " [env: " + strings.Join(env, ", ") + "]")
+}
+
+func optionNames(opt *optDef) ([]string, bool) {
+	names := make([]string, 0)
+	for _, f := range opt.posixOpts {
+		if f != 0 {
+			names = append(names, "-"+string(f))
+		}
+	}
+	long := false
+	for _, f := range opt.longOpts {
+		if !strings.HasPrefix(f, "--") {
+			continue
+		}
+		if opt.noArg && slices.Contains(opt.negated, "--no-"+f[2:]) {
+			f = "--[no-]" + f[2:]
+		}
+		names = append(names, f)
+		long = true
+	}
+	return names, long
+}
+
+func negatedNames(opt *optDef) []string {
+	if opt.noArg {
+		return nil
+	}
+	return opt.negated
+}
+
+func optionArg(opt *optDef, long bool) (string, string, string) {
+	switch {
+	case opt.argType == "":
+		return "", "", ""
+	case long && opt.optionalArg:
+		return "[=", opt.argType, "]"
+	case long:
+		return "=", opt.argType, ""
+	case opt.optionalArg:
+		return "[", opt.argType, "]"
+	default:
+		return " ", opt.argType, ""
+	}
+}
+
+func optionDesc(opt *optDef) string {
+	desc := make([]string, 0)
+	if opt.required {
+		desc = append(desc, "required")
+	}
+	if opt.multiple {
+		desc = append(desc, "multiple")
+	}
+	if opt.help != "" {
+		desc = append(desc, opt.help)
+	}
+	return strings.Join(desc, ", ")
+}
+
+func helpColumn(sections ...[]helpRow) int {
+	column := 0
+	for _, rows := range sections {
+		for _, row := range rows {
+			if l := utf8.RuneCountInString(row.term); l <= helpTermWidth && l > column {
+				column = l
+			}
+		}
+	}
+	return helpIndent + column + helpGap
+}
+
+func printRows(w io.Writer, rows []helpRow, column int, width int) {
+	for _, row := range rows {
+		term := strings.Repeat(" ", helpIndent) + row.term
+		lines := wrapText(row.desc, max(width-column, helpMinDesc))
+		if len(lines) == 0 {
+			fmt.Fprintln(w, term)
+			continue
+		}
+		if utf8.RuneCountInString(term)+helpGap > column {
+			fmt.Fprintln(w, term)
+			term = ""
+		}
+		for _, line := range lines {
+			fmt.Fprintln(w, term+strings.Repeat(" ", column-utf8.RuneCountInString(term))+line)
+			term = ""
+		}
+	}
+}
+
+func wrapText(text string, width int) []string {
+	lines := make([]string, 0)
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line == "" {
+			line = word
+		} else if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width {
+			line += " " + word
+		} else {
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
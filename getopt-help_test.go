@@ -0,0 +1,94 @@
+package getopt
+
+import (
+	"testing"
+)
+
+func TestGetOpt_HelpLayout(t *testing.T) {
+	opts := New().WithDefaults("prog", "1.0", "Usage: prog [options] command").WithHelpWidth(60)
+	_, _ = opts.StringValue('o', "--output", true, "Output file name")
+	_, _ = opts.IntOptional('O', "--level", 0, 2, "Optimization level")
+	_, _ = opts.StringValue('I', "", false, "Include directory")
+	_, _ = opts.Flag('c', "--color", "Colorize output")
+	_ = opts.Negatable("--color")
+	_, _ = opts.StringValue(0, "--a-very-long-option-name-indeed", false, "Long options push their description to the next line and wrap it with hanging indent")
+	cmd := opts.Command("run", "Run the program", nil)
+	_, _ = cmd.StringPositional("FILE", true, "File to run")
+	want := `Usage: prog [options] command
+  -h, --help           Print help
+  -V, --version        Print version
+  -o, --output=string  required, Output file name
+  -O, --level[=int]    Optimization level
+  -I string            Include directory
+  -c, --[no-]color     Colorize output
+  --a-very-long-option-name-indeed=string
+                       Long options push their description
+                       to the next line and wrap it with
+                       hanging indent
+Commands:
+  run                  Run the program
+`
+	if got := opts.HelpString(); got != want {
+		t.Errorf("HelpString() = \n%s\nwant\n%s", got, want)
+	}
+	want = `Usage: prog run [options] FILE
+prog run: Run the program
+  -h, --help           Print help
+Arguments:
+  FILE                 File to run
+Global options:
+  -V, --version        Print version
+  -o, --output=string  required, Output file name
+  -O, --level[=int]    Optimization level
+  -I string            Include directory
+  -c, --[no-]color     Colorize output
+  --a-very-long-option-name-indeed=string
+                       Long options push their description
+                       to the next line and wrap it with
+                       hanging indent
+`
+	if got := cmd.HelpString(); got != want {
+		t.Errorf("HelpString() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGetOpt_HelpWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "42")
+	opts := New()
+	if got := opts.terminalWidth(); got != 42 {
+		t.Errorf("terminalWidth() = %v, want 42 from $COLUMNS", got)
+	}
+	cmd := opts.WithHelpWidth(100).Command("run", "", nil)
+	if got := cmd.terminalWidth(); got != 100 {
+		t.Errorf("terminalWidth() = %v, want 100 inherited from parent", got)
+	}
+	t.Setenv("COLUMNS", "")
+	if got := New().terminalWidth(); got != 80 {
+		t.Errorf("terminalWidth() = %v, want default 80", got)
+	}
+}
+
+func Test_wrapText(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"", 10, []string{}},
+		{"short", 10, []string{"short"}},
+		{"one two three four", 9, []string{"one two", "three", "four"}},
+		{"unbreakable-word here", 5, []string{"unbreakable-word", "here"}},
+	}
+	for _, test := range tests {
+		got := wrapText(test.text, test.width)
+		if len(got) != len(test.want) {
+			t.Errorf("wrapText(%q, %v) = %q, want %q", test.text, test.width, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("wrapText(%q, %v) = %q, want %q", test.text, test.width, got, test.want)
+			}
+		}
+	}
+}
@@ -66,7 +66,6 @@ func (opts *GetOpt) IntValueV(flags []rune, longFlags []string, required bool, h
 	def.argConv = func(arg string) error {
 		var err error
 		result, err = parseInt(arg)
-		def.count++
 		return err
 	}
 	def.argReset = func() {
@@ -85,12 +84,38 @@ func (opts *GetOpt) IntDefaultV(flags []rune, longFlags []string, value int64, h
 		posixOpts: flags,
 		longOpts:  longFlags,
 		help:      help,
+		defValue:  strconv.FormatInt(value, 10),
 		argType:   "int",
 	}
 	def.argConv = func(arg string) error {
 		var err error
 		result, err = parseInt(arg)
-		def.count++
+		return err
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.safeAdd(def)
+}
+
+func (opts *GetOpt) IntOptional(flag rune, longFlag string, value int64, implicit int64, help string) (*int64, error) {
+	return opts.IntOptionalV([]rune{flag}, []string{longFlag}, value, implicit, help)
+}
+
+func (opts *GetOpt) IntOptionalV(flags []rune, longFlags []string, value int64, implicit int64, help string) (*int64, error) {
+	result := value
+	def := optDef{
+		posixOpts:   flags,
+		longOpts:    longFlags,
+		help:        help,
+		defValue:    strconv.FormatInt(value, 10),
+		optionalArg: true,
+		implicit:    strconv.FormatInt(implicit, 10),
+		argType:     "int",
+	}
+	def.argConv = func(arg string) error {
+		var err error
+		result, err = parseInt(arg)
 		return err
 	}
 	def.argReset = func() {
@@ -109,12 +134,13 @@ func (opts *GetOpt) IntListV(flags []rune, longFlags []string, help string) (*[]
 		posixOpts: flags,
 		longOpts:  longFlags,
 		help:      help,
+		multiple:  true,
+		list:      true,
 		argType:   "int",
 	}
 	def.argConv = func(arg string) error {
 		if value, err := parseInt(arg); err == nil {
 			result = append(result, value)
-			def.count++
 			return nil
 		} else {
 			return err
@@ -125,3 +151,41 @@ func (opts *GetOpt) IntListV(flags []rune, longFlags []string, help string) (*[]
 	}
 	return &result, opts.safeAdd(def)
 }
+
+func (opts *GetOpt) IntPositional(name string, required bool, help string) (*int64, error) {
+	return opts.intPositional(name, required, 0, help)
+}
+
+func (opts *GetOpt) IntPositionalDefault(name string, value int64, help string) (*int64, error) {
+	return opts.intPositional(name, false, value, help)
+}
+
+func (opts *GetOpt) intPositional(name string, required bool, value int64, help string) (*int64, error) {
+	result := value
+	def := positional(name, required, help)
+	def.argConv = func(arg string) error {
+		var err error
+		result, err = parseInt(arg)
+		return err
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.addPositional(def)
+}
+
+func (opts *GetOpt) IntPositionalList(name string, min int, max int, help string) (*[]int64, error) {
+	result := make([]int64, 0)
+	def := positionalList(name, min, max, help)
+	def.argConv = func(arg string) error {
+		value, err := parseInt(arg)
+		if err == nil {
+			result = append(result, value)
+		}
+		return err
+	}
+	def.argReset = func() {
+		result = make([]int64, 0)
+	}
+	return &result, opts.addPositional(def)
+}
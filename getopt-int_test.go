@@ -38,6 +38,17 @@ func TestGetOpt_Int(t *testing.T) {
 			wantValue:   10,
 			wantParseOk: true,
 		},
+		{
+			name: "optional longopt separate arg",
+			init: func(getopt *GetOpt) (*int64, error) {
+				return getopt.IntValue('f', "--int", false, "help")
+			},
+			args:        []string{"prog", "--int", "12"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   12,
+			wantParseOk: true,
+		},
 		{
 			name: "optional longopt no arg should fail",
 			init: func(getopt *GetOpt) (*int64, error) {
@@ -115,6 +126,61 @@ func TestGetOpt_Int(t *testing.T) {
 			wantValue:   4,
 			wantParseOk: true,
 		},
+		{
+			name: "optional argument absent",
+			init: func(getopt *GetOpt) (*int64, error) {
+				return getopt.IntOptional('f', "--int", 1, 2, "help")
+			},
+			args:        []string{"prog"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   1,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument implicit",
+			init: func(getopt *GetOpt) (*int64, error) {
+				return getopt.IntOptional('f', "--int", 1, 2, "help")
+			},
+			args:        []string{"prog", "-f"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   2,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument inline",
+			init: func(getopt *GetOpt) (*int64, error) {
+				return getopt.IntOptional('f', "--int", 1, 2, "help")
+			},
+			args:        []string{"prog", "-f10"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   10,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument longopt inline",
+			init: func(getopt *GetOpt) (*int64, error) {
+				return getopt.IntOptional('f', "--int", 1, 2, "help")
+			},
+			args:        []string{"prog", "--int=10"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   10,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument not taken from next word",
+			init: func(getopt *GetOpt) (*int64, error) {
+				return getopt.IntOptional('f', "--int", 1, 2, "help")
+			},
+			args:        []string{"prog", "--int", "10"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   2,
+			wantParseOk: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -0,0 +1,111 @@
+package getopt
+
+import (
+	"fmt"
+	"os"
+)
+
+type Getopt struct {
+	OptArg   string
+	OptInd   int
+	OptOpt   rune
+	OptErr   bool
+	args     []string
+	cfg      getoptConfig
+	nextChar int
+	lastInd  int
+}
+
+func NewGetopt(args []string, options string) (*Getopt, error) {
+	if cfg, err := newGetoptConfig(options); err != nil {
+		return nil, err
+	} else {
+		return &Getopt{
+			OptInd:  1,
+			OptErr:  true,
+			args:    args,
+			cfg:     cfg,
+			lastInd: 1,
+		}, nil
+	}
+}
+
+func (g *Getopt) Next() rune {
+	g.OptArg = ""
+	if g.OptInd <= 0 {
+		g.OptInd = 1
+		g.nextChar = 0
+	} else if g.OptInd != g.lastInd {
+		g.nextChar = 0
+	}
+	defer func() {
+		g.lastInd = g.OptInd
+	}()
+	if g.nextChar == 0 {
+		if g.OptInd >= len(g.args) {
+			return -1
+		}
+		arg := g.args[g.OptInd]
+		if arg == "--" {
+			g.OptInd++
+			return -1
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			if g.cfg.positionalAsArgs {
+				g.OptArg = arg
+				g.OptInd++
+				return 1
+			}
+			return -1
+		}
+		g.nextChar = 1
+	}
+	runes := []rune(g.args[g.OptInd])
+	ch := runes[g.nextChar]
+	g.nextChar++
+	last := g.nextChar == len(runes)
+	optType, found := g.cfg.optmap[ch]
+	if !found || ch == ':' {
+		if last {
+			g.advance()
+		}
+		return g.fail(ch, ErrUnknownOption)
+	}
+	if optType == NoArgument {
+		if last {
+			g.advance()
+		}
+		return ch
+	}
+	if !last {
+		g.OptArg = string(runes[g.nextChar:])
+		g.advance()
+		return ch
+	}
+	g.advance()
+	if optType == RequiredArgument {
+		if g.OptInd >= len(g.args) {
+			return g.fail(ch, ErrMissingArgument)
+		}
+		g.OptArg = g.args[g.OptInd]
+		g.OptInd++
+	}
+	return ch
+}
+
+func (g *Getopt) advance() {
+	g.OptInd++
+	g.nextChar = 0
+}
+
+func (g *Getopt) fail(ch rune, err error) rune {
+	g.OptOpt = ch
+	optErr := &OptionError{Code: '?', Option: "-" + string(ch), Err: err}
+	if err == ErrMissingArgument && g.cfg.dontPrintErrors {
+		optErr.Code = ':'
+	}
+	if g.OptErr && !g.cfg.dontPrintErrors {
+		_, _ = fmt.Fprintln(os.Stderr, g.args[0]+":", optErr)
+	}
+	return optErr.Code
+}
@@ -0,0 +1,174 @@
+package getopt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetopt_Next(t *testing.T) {
+	type step struct {
+		Opt    rune
+		OptArg string
+		OptInd int
+		OptOpt rune
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		options string
+		want    []step
+	}{
+		{
+			name:    "posixStopsAtOperand",
+			args:    []string{"cmd", "-ab", "-ofile", "-o", "out", "-t", "zeta", "-a"},
+			options: "abo:t::",
+			want: []step{
+				{'a', "", 1, 0},
+				{'b', "", 2, 0},
+				{'o', "file", 3, 0},
+				{'o', "out", 5, 0},
+				{'t', "", 6, 0},
+				{-1, "", 6, 0},
+			},
+		},
+		{
+			name:    "doubleDashIsConsumed",
+			args:    []string{"cmd", "-a", "--", "-b"},
+			options: "ab",
+			want: []step{
+				{'a', "", 2, 0},
+				{-1, "", 3, 0},
+			},
+		},
+		{
+			name:    "loneDashIsOperand",
+			args:    []string{"cmd", "-a", "-", "-b"},
+			options: "ab",
+			want: []step{
+				{'a', "", 2, 0},
+				{-1, "", 2, 0},
+			},
+		},
+		{
+			name:    "errorsWithColon",
+			args:    []string{"cmd", "-xa", "-o"},
+			options: ":ao:",
+			want: []step{
+				{'?', "", 1, 'x'},
+				{'a', "", 2, 'x'},
+				{':', "", 3, 'o'},
+				{-1, "", 3, 'o'},
+			},
+		},
+		{
+			name:    "errorsWithoutColon",
+			args:    []string{"cmd", "-o"},
+			options: "o:",
+			want: []step{
+				{'?', "", 2, 'o'},
+				{-1, "", 2, 'o'},
+			},
+		},
+		{
+			name:    "returnInOrder",
+			args:    []string{"cmd", "alpha", "-a", "beta"},
+			options: "-a",
+			want: []step{
+				{1, "alpha", 2, 0},
+				{'a', "", 3, 0},
+				{1, "beta", 4, 0},
+				{-1, "", 4, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGetopt(tt.args, tt.options)
+			if err != nil {
+				t.Fatalf("NewGetopt() error = %v", err)
+			}
+			g.OptErr = false
+			got := make([]step, 0)
+			for len(got) < len(tt.want) {
+				c := g.Next()
+				got = append(got, step{c, g.OptArg, g.OptInd, g.OptOpt})
+				if c == -1 {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Next() got/want =\n %v\n %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetopt_Reset(t *testing.T) {
+	g, err := NewGetopt([]string{"cmd", "-ab", "-c"}, "abc")
+	if err != nil {
+		t.Fatalf("NewGetopt() error = %v", err)
+	}
+	if c := g.Next(); c != 'a' {
+		t.Errorf("Next() = %c, want a", c)
+	}
+	g.OptInd = 0
+	got := make([]rune, 0)
+	for c := g.Next(); c != -1; c = g.Next() {
+		got = append(got, c)
+	}
+	if !reflect.DeepEqual(got, []rune{'a', 'b', 'c'}) {
+		t.Errorf("Next() after reset = %c", got)
+	}
+	g.OptInd = 2
+	if c := g.Next(); c != 'c' || g.OptInd != 3 {
+		t.Errorf("Next() after OptInd = 2 is %c, OptInd = %d", c, g.OptInd)
+	}
+}
+
+func TestGetopt_Tokenize(t *testing.T) {
+	tests := [][]string{
+		{"cmd", "-ab", "x", "-c5"},
+		{"cmd", "-bfile", "-c", "--", "-a"},
+		{"cmd", "-a", "-", "-b", "x"},
+		{"cmd", "-b", "-a", "operand", "-c"},
+		{"cmd", "operand", "-a"},
+	}
+	for _, options := range []string{"ab:c::", "+ab:c::"} {
+		for _, args := range tests {
+			g, err := NewGetopt(args, options)
+			if err != nil {
+				t.Fatalf("NewGetopt() error = %v", err)
+			}
+			got := make([]Option, 0)
+			for c := g.Next(); c != -1; c = g.Next() {
+				opt := Option{Opt: "-" + string(c)}
+				if c != 'a' {
+					opt.Arg = mkstr(g.OptArg)
+				}
+				got = append(got, opt)
+			}
+			for _, operand := range args[g.OptInd:] {
+				got = append(got, Option{Arg: mkstr(operand)})
+			}
+			want, err := Tokenize(args, "+ab:c::")
+			if err != nil {
+				t.Fatalf("Tokenize() error = %v", err)
+			}
+			for i := range want {
+				if want[i].Opt == "-c" && want[i].Arg == nil {
+					want[i].Arg = mkstr("")
+				}
+			}
+			if !reflect.DeepEqual(got, withoutPositions(want)) {
+				t.Errorf("Getopt(%q, %q) = %v, Tokenize with \"+\" = %v", args, options, got, want)
+			}
+		}
+	}
+	args := []string{"cmd", "operand", "-a"}
+	if g, _ := NewGetopt(args, "a"); g.Next() != -1 || g.OptInd != 1 {
+		t.Errorf("Getopt is expected to stop at the first operand")
+	}
+	if opts, _ := Tokenize(args, "a"); len(opts) != 2 || opts[0].Opt != "-a" {
+		t.Errorf("Tokenize without \"+\" is expected to permute operands, got %v", opts)
+	}
+}
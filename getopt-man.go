@@ -0,0 +1,142 @@
+package getopt
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+func (opts *GetOpt) AddManPage() {
+	def := optDef{
+		longOpts: []string{"--generate-man"},
+		help:     "Print man page",
+		noArg:    true,
+		hidden:   true,
+	}
+	def.argConv = func(string) error {
+		opts.done = true
+		return opts.WriteManPage(opts.output())
+	}
+	_ = opts.safeAdd(def)
+}
+
+func (opts *GetOpt) WriteManPage(w io.Writer) error {
+	var page strings.Builder
+	opts.writeManPage(&page)
+	_, err := io.WriteString(w, page.String())
+	return err
+}
+
+func (opts *GetOpt) writeManPage(w io.Writer) {
+	name := opts.fullName()
+	title := strings.ToUpper(strings.ReplaceAll(name, " ", "-"))
+	fmt.Fprintf(w, ".TH %s 1 \"\" %s \"User Commands\"\n", roffQuote(title), roffQuote(strings.TrimSpace(name+" "+opts.version)))
+	fmt.Fprintln(w, ".SH NAME")
+	if summary := opts.summary(); summary != "" {
+		fmt.Fprintln(w, roffEscape(strings.ReplaceAll(name, " ", "-"))+" \\- "+roffEscape(summary))
+	} else {
+		fmt.Fprintln(w, roffEscape(strings.ReplaceAll(name, " ", "-")))
+	}
+	fmt.Fprintln(w, ".SH SYNOPSIS")
+	fmt.Fprintln(w, ".B "+roffEscape(name))
+	for _, arg := range opts.synopsisArgs() {
+		fmt.Fprintln(w, ".I "+roffEscape(arg))
+	}
+	if len(opts.description) > 0 {
+		fmt.Fprintln(w, ".SH DESCRIPTION")
+		for _, desc := range opts.description {
+			fmt.Fprintln(w, roffLine(desc))
+			fmt.Fprintln(w, ".br")
+		}
+	}
+	env := make([]*optDef, 0)
+	header := ".SH OPTIONS"
+	for cmd := opts; cmd != nil; cmd = cmd.parent {
+		for _, opt := range cmd.optionList {
+			if opt.hidden || (cmd != opts && opts.shadows(opt)) {
+				continue
+			}
+			if header != "" {
+				fmt.Fprintln(w, header)
+				header = ""
+			}
+			fmt.Fprintln(w, ".TP")
+			fmt.Fprintln(w, manOptionTerm(opt))
+			fmt.Fprintln(w, roffLine(optionDesc(opt)))
+			if len(opt.env) > 0 {
+				env = append(env, opt)
+			}
+		}
+	}
+	if len(opts.commands) > 0 {
+		fmt.Fprintln(w, ".SH COMMANDS")
+		for _, cmd := range opts.commands {
+			fmt.Fprintln(w, ".TP")
+			fmt.Fprintln(w, "\\fB"+roffEscape(cmd.name)+"\\fR")
+			fmt.Fprintln(w, roffLine(cmd.commandHelp))
+		}
+	}
+	rows := make([]helpRow, 0)
+	for _, opt := range env {
+		names, _ := optionNames(opt)
+		for _, name := range opt.env {
+			rows = append(rows, helpRow{name, "Default value for " + strings.Join(names, ", ") + ": " + opt.help})
+		}
+	}
+	for _, pos := range opts.positionals {
+		for _, name := range pos.env {
+			rows = append(rows, helpRow{name, "Default value for " + pos.name + ": " + pos.help})
+		}
+	}
+	if len(rows) > 0 {
+		fmt.Fprintln(w, ".SH ENVIRONMENT")
+		for _, row := range rows {
+			fmt.Fprintln(w, ".TP")
+			fmt.Fprintln(w, "\\fB"+roffEscape(row.term)+"\\fR")
+			fmt.Fprintln(w, roffLine(row.desc))
+		}
+	}
+}
+
+func (opts *GetOpt) summary() string {
+	if opts.commandHelp != "" {
+		return opts.commandHelp
+	}
+	for _, desc := range opts.description {
+		if desc = strings.TrimSpace(desc); desc != "" && !strings.HasPrefix(desc, "Usage:") {
+			return desc
+		}
+	}
+	return ""
+}
+
+func manOptionTerm(opt *optDef) string {
+	names, long := optionNames(opt)
+	for i, name := range names {
+		names[i] = "\\fB" + roffEscape(name) + "\\fR"
+	}
+	term := strings.Join(names, ", ")
+	if prefix, arg, suffix := optionArg(opt, long); arg != "" {
+		term += roffEscape(prefix) + "\\fI" + roffEscape(arg) + "\\fR" + roffEscape(suffix)
+	}
+	for _, name := range negatedNames(opt) {
+		term += ", \\fB" + roffEscape(name) + "\\fR"
+	}
+	return term
+}
+
+func roffEscape(text string) string {
+	return strings.NewReplacer("\\", "\\e", "-", "\\-").Replace(text)
+}
+
+func roffLine(text string) string {
+	text = roffEscape(text)
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = "\\&" + text
+	}
+	return text
+}
+
+func roffQuote(text string) string {
+	return "\"" + strings.ReplaceAll(roffEscape(text), "\"", "\"\"") + "\""
+}
@@ -0,0 +1,100 @@
+package getopt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetOpt_WriteManPage(t *testing.T) {
+	type target struct {
+		Output string `flag:"o,output" help:"Output file name" env:"PROG_OUTPUT"`
+		Level  int    `flag:"O,level" implicit:"2" help:"Optimization level"`
+		Quiet  bool   `flag:"q" help:".quiet mode"`
+	}
+	opts := New().WithDefaults("prog", "1.0", "Compiles files.")
+	opts.AddManPage()
+	if err := opts.bind(&target{}); err != nil {
+		t.Fatalf("bind() unexpected error %v", err)
+	}
+	_, _ = opts.StringPositionalList("FILE", 1, 0, "input files")
+	_ = opts.Env("FILE", "PROG_FILES")
+	var buf strings.Builder
+	if err := opts.WriteManPage(&buf); err != nil {
+		t.Fatalf("WriteManPage() unexpected error %v", err)
+	}
+	want := `.TH "PROG" 1 "" "prog 1.0" "User Commands"
+.SH NAME
+prog \- Compiles files.
+.SH SYNOPSIS
+.B prog
+.I [options]
+.I FILE...
+.SH DESCRIPTION
+Compiles files.
+.br
+.SH OPTIONS
+.TP
+\fB\-h\fR, \fB\-\-help\fR
+Print help
+.TP
+\fB\-V\fR, \fB\-\-version\fR
+Print version
+.TP
+\fB\-o\fR, \fB\-\-output\fR=\fIstring\fR
+Output file name
+.TP
+\fB\-O\fR, \fB\-\-level\fR[=\fIint\fR]
+Optimization level
+.TP
+\fB\-q\fR
+\&.quiet mode
+.SH ENVIRONMENT
+.TP
+\fBPROG_OUTPUT\fR
+Default value for \-o, \-\-output: Output file name
+.TP
+\fBPROG_FILES\fR
+Default value for FILE: input files
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteManPage() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGetOpt_AddManPage(t *testing.T) {
+	var stdout strings.Builder
+	opts := New().WithDefaults("tool", "2.0").WithOutput(&stdout, nil)
+	opts.AddManPage()
+	deploy := opts.Command("deploy", "Deploy application", nil)
+	if _, err := opts.Parse([]string{"tool", "--generate-man"}, false); err != nil || !opts.Done() {
+		t.Fatalf("Parse() error = %v, done = %v", err, opts.Done())
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, ".TH \"TOOL\" 1") || !strings.Contains(got, ".SH COMMANDS\n.TP\n\\fBdeploy\\fR\nDeploy application\n") {
+		t.Errorf("--generate-man output = %q", got)
+	}
+	if strings.Contains(opts.HelpString(), "generate-man") {
+		t.Errorf("--generate-man should be hidden from help")
+	}
+	var buf strings.Builder
+	_ = deploy.WriteManPage(&buf)
+	if got := buf.String(); !strings.Contains(got, "\n.SH NAME\ntool\\-deploy \\- Deploy application\n") {
+		t.Errorf("command WriteManPage() = %q", got)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestGetOpt_WriteManPageError(t *testing.T) {
+	opts := New().WithDefaults("tool", "2.0", "Usage: tool [options]", "Manages things.")
+	if err := opts.WriteManPage(failingWriter{}); err == nil {
+		t.Errorf("WriteManPage() expected to fail")
+	}
+	if got := opts.summary(); got != "Manages things." {
+		t.Errorf("summary() = %q", got)
+	}
+}
@@ -2,7 +2,6 @@ package getopt
 
 import (
 	"errors"
-	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -10,23 +9,58 @@ import (
 )
 
 func (opts *GetOpt) Marshal(target interface{}, argv []string, posix bool) ([]string, error) {
+	if err := opts.bind(target); err != nil {
+		opts.done = true
+		return nil, err
+	}
+	return opts.Parse(argv, posix)
+}
+
+func (opts *GetOpt) bind(target interface{}) error {
 	targetValue := reflect.ValueOf(target).Elem()
 	if targetValue.Kind() == reflect.Ptr {
 		targetValue = reflect.Indirect(targetValue)
 	}
 	if targetValue.Kind() != reflect.Struct {
-		opts.done = true
-		return nil, errors.New("struct pointer expected, " + targetValue.Kind().String() + " receved")
+		return errors.New("struct pointer expected, " + targetValue.Kind().String() + " receved")
 	}
 	if !targetValue.CanAddr() {
-		opts.done = true
-		return nil, errors.New("struct pointer expected, " + targetValue.Kind().String() + " receved")
+		return errors.New("struct pointer expected, " + targetValue.Kind().String() + " receved")
 	}
+	groups := groupCollector{named: make(map[string]*optGroup)}
+	positionals := make(map[int]posDef)
+	var rest *posDef
 	for i, I := 0, targetValue.NumField(); i < I; i++ {
 		fieldType := targetValue.Type().Field(i)
-		if found, ok := fieldType.Tag.Lookup("flag"); ok {
+		if name, ok := fieldType.Tag.Lookup("cmd"); ok {
+			if !fieldType.IsExported() {
+				return errors.New("can't use commands for unexported fieldType " + fieldType.Name)
+			}
+			if err := opts.bindCommand(name, fieldType, targetValue.Field(i)); err != nil {
+				return err
+			}
+		} else if found, ok := fieldType.Tag.Lookup("arg"); ok {
+			index, err := strconv.Atoi(found)
+			if err != nil || index < 0 {
+				return errors.New("invalid arg index " + found + " for " + fieldType.Name)
+			} else if _, exists := positionals[index]; exists {
+				return errors.New("duplicate arg index " + found + " for " + fieldType.Name)
+			}
+			if positionals[index], err = opts.bindPositional(targetValue.Addr(), fieldType, targetValue.Field(i), false); err != nil {
+				return err
+			}
+		} else if _, ok := fieldType.Tag.Lookup("args"); ok {
+			if rest != nil {
+				return errors.New("duplicate args for " + fieldType.Name)
+			}
+			def, err := opts.bindPositional(targetValue.Addr(), fieldType, targetValue.Field(i), true)
+			if err != nil {
+				return err
+			}
+			rest = &def
+		} else if found, ok := fieldType.Tag.Lookup("flag"); ok {
 			if !fieldType.IsExported() {
-				return nil, errors.New("can't use flags for unexported fieldType " + fieldType.Name)
+				return errors.New("can't use flags for unexported fieldType " + fieldType.Name)
 			}
 			fieldValue := targetValue.Field(i)
 			synonyms := strings.Split(found, ",")
@@ -43,325 +77,534 @@ func (opts *GetOpt) Marshal(target interface{}, argv []string, posix bool) ([]st
 				trigger = value
 			case func(str string) error:
 				callback = value
-			case string:
-				callback = func(strval string) error {
-					fieldValue.Set(reflect.ValueOf(strval))
-					return nil
-				}
-			case []string:
-				callback = func(strval string) error {
-					value = append(value, strval)
-					fieldValue.Set(reflect.ValueOf(value))
-					return nil
-				}
-			case map[string]string:
-				callback = func(strval string) error {
-					key, val := getKeyValue(strval)
-					if value == nil {
-						value = make(map[string]string)
-					}
-					value[key] = val
+			case bool:
+				err = opts.flagFunc(flags, longopts, func(value bool) {
 					fieldValue.Set(reflect.ValueOf(value))
-					return nil
-				}
-			case uint64:
-				callback = func(strval string) error {
-					value, err := strconv.ParseUint(strval, 0, 64)
-					if err == nil {
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
-				}
-			case []uint64:
-				callback = func(strval string) error {
-					val, err := strconv.ParseUint(strval, 0, 64)
-					if err == nil {
-						value = append(value, val)
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
-				}
-			case map[string]uint64:
-				callback = func(strval string) error {
-					key, sval := getKeyValue(strval)
-					if val, err := strconv.ParseUint(sval, 0, 64); err != nil {
-						return err
-					} else {
-						if value == nil {
-							value = make(map[string]uint64)
-						}
-						value[key] = val
-						fieldValue.Set(reflect.ValueOf(value))
-						return nil
-					}
-				}
-			case uint:
-				callback = func(strval string) error {
-					value, err := strconv.ParseUint(strval, 0, 32)
-					if err == nil {
-						fieldValue.Set(reflect.ValueOf(uint(value)))
-					}
-					return err
-				}
-			case []uint:
-				callback = func(strval string) error {
-					val, err := strconv.ParseUint(strval, 0, 32)
-					if err == nil {
-						value = append(value, uint(val))
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
-				}
-			case map[string]uint:
-				callback = func(strval string) error {
-					key, sval := getKeyValue(strval)
-					if val, err := strconv.ParseUint(sval, 0, 32); err != nil {
-						return err
-					} else {
-						if value == nil {
-							value = make(map[string]uint)
-						}
-						value[key] = uint(val)
-						fieldValue.Set(reflect.ValueOf(value))
-						return nil
-					}
-				}
-			case int64:
-				callback = func(strval string) error {
-					value, err := strconv.ParseInt(strval, 0, 64)
-					if err == nil {
-						fieldValue.Set(reflect.ValueOf(value))
+				}, help)
+				if err == nil {
+					var val bool
+					if val, err = boolDefault(fieldType.Tag); err == nil && val {
+						fieldValue.Set(reflect.ValueOf(val))
 					}
-					return err
 				}
-			case []int64:
-				callback = func(strval string) error {
-					val, err := strconv.ParseInt(strval, 0, 64)
-					if err == nil {
-						value = append(value, val)
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
+			default:
+				if count, _ := strconv.ParseBool(fieldType.Tag.Get("count")); count && fieldType.Type.Kind() == reflect.Int {
+					err = opts.bindCounter(fieldType, fieldValue, flags, longopts, help)
+				} else if callback = opts.fieldCallback(fieldType, fieldValue); callback == nil {
+					return errors.New("unsupported type " + fieldType.Type.Kind().String() + " for " + fieldType.Name)
 				}
-			case map[string]int64:
-				callback = func(strval string) error {
-					key, sval := getKeyValue(strval)
-					if val, err := strconv.ParseInt(sval, 0, 64); err != nil {
-						return err
-					} else {
-						if value == nil {
-							value = make(map[string]int64)
-						}
-						value[key] = val
-						fieldValue.Set(reflect.ValueOf(value))
-						return nil
-					}
+			}
+			if found, ok := fieldType.Tag.Lookup("choices"); ok && callback != nil {
+				callback = choiceCallback(strings.Split(found, ","), callback)
+			}
+			if callback != nil {
+				if implicit, ok := fieldType.Tag.Lookup("implicit"); ok {
+					err = opts.ArgFuncOptionalV(flags, longopts, implicit, callback, help)
+				} else {
+					err = opts.ArgFuncV(flags, longopts, callback, help)
 				}
-			case int:
-				callback = func(strval string) error {
-					value, err := strconv.ParseInt(strval, 0, 32)
-					if err == nil {
-						fieldValue.Set(reflect.ValueOf(int(value)))
-					}
-					return err
+				if val, ok := fieldType.Tag.Lookup("default"); ok && err == nil {
+					err = callback(val)
 				}
-			case []int:
-				callback = func(strval string) error {
-					val, err := strconv.ParseInt(strval, 0, 32)
-					if err == nil {
-						value = append(value, int(val))
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
-				}
-			case map[string]int:
-				callback = func(strval string) error {
-					key, sval := getKeyValue(strval)
-					if val, err := strconv.ParseInt(sval, 0, 32); err != nil {
-						return err
-					} else {
-						if value == nil {
-							value = make(map[string]int)
-						}
-						value[key] = int(val)
-						fieldValue.Set(reflect.ValueOf(value))
-						return nil
+			} else if trigger != nil {
+				err = opts.FlagFuncV(flags, longopts, trigger, help)
+				if err == nil {
+					var val bool
+					if val, err = boolDefault(fieldType.Tag); err == nil && val {
+						trigger()
 					}
 				}
-			case bool:
-				err = opts.FlagFuncV(flags, longopts, func() error {
-					fieldValue.Set(reflect.ValueOf(true))
-					return nil
-				}, help)
-			case float64:
-				callback = func(strval string) error {
-					value, err := strconv.ParseFloat(strval, 64)
-					if err == nil {
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
+			}
+			if err != nil {
+				return err
+			}
+			var key string
+			if len(longopts) > 0 {
+				key = longopts[0]
+			} else {
+				key = "-" + string(flags[0])
+			}
+			if found, ok := fieldType.Tag.Lookup("choices"); ok && callback != nil {
+				item := opts.optionMap[key]
+				item.choices = strings.Split(found, ",")
+				item.argType = strings.Join(item.choices, "|")
+			}
+			if item, exists := opts.optionMap[key]; exists {
+				if found, ok := fieldType.Tag.Lookup("env"); ok {
+					item.env = append(item.env, found)
 				}
-			case []float64:
-				callback = func(strval string) error {
-					val, err := strconv.ParseFloat(strval, 64)
-					if err == nil {
-						value = append(value, val)
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
+				if kind := fieldType.Type.Kind(); callback != nil && (kind == reflect.Slice || kind == reflect.Map) {
+					item.multiple, item.list = true, true
+					item.argReset = clearField(fieldValue)
 				}
-			case map[string]float64:
-				callback = func(strval string) error {
-					key, sval := getKeyValue(strval)
-					if val, err := strconv.ParseFloat(sval, 64); err != nil {
-						return err
-					} else {
-						if value == nil {
-							value = make(map[string]float64)
-						}
-						value[key] = val
-						fieldValue.Set(reflect.ValueOf(value))
-						return nil
-					}
+				if argType, ok := fieldType.Tag.Lookup("type"); ok && callback != nil {
+					item.argType = argType
+				} else if callback != nil && len(item.choices) == 0 {
+					item.argType = fieldArgType(fieldType.Type)
 				}
-			case float32:
-				callback = func(strval string) error {
-					value, err := strconv.ParseFloat(strval, 32)
-					if err == nil {
-						fieldValue.Set(reflect.ValueOf(float32(value)))
-					}
-					return err
+				item.defValue = fieldType.Tag.Get("default")
+			}
+			if found, ok := fieldType.Tag.Lookup("complete"); ok {
+				completer, err := methodCompleter(targetValue.Addr(), found)
+				if err == nil {
+					err = opts.Completer(key, completer)
 				}
-			case []float32:
-				callback = func(strval string) error {
-					val, err := strconv.ParseFloat(strval, 32)
-					if err == nil {
-						value = append(value, float32(val))
-						fieldValue.Set(reflect.ValueOf(value))
-					}
+				if err != nil {
 					return err
 				}
-			case map[string]float32:
-				callback = func(strval string) error {
-					key, sval := getKeyValue(strval)
-					if val, err := strconv.ParseFloat(sval, 32); err != nil {
-						return err
-					} else {
-						if value == nil {
-							value = make(map[string]float32)
-						}
-						value[key] = float32(val)
-						fieldValue.Set(reflect.ValueOf(value))
-						return nil
-					}
-				}
-			case time.Time:
-				callback = func(strval string) error {
-					value, err := time.Parse(time.RFC3339, strval)
-					if err == nil {
-						fieldValue.Set(reflect.ValueOf(value))
-					}
+			}
+			if negatable, _ := strconv.ParseBool(fieldType.Tag.Get("negatable")); negatable {
+				if err := opts.Negatable(key); err != nil {
 					return err
 				}
-			case []time.Time:
-				callback = func(strval string) error {
-					val, err := time.Parse(time.RFC3339, strval)
-					if err == nil {
-						value = append(value, val)
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
+			}
+			groups.collect(fieldType.Tag, key)
+		}
+	}
+	for index := 0; index < len(positionals); index++ {
+		def, found := positionals[index]
+		if !found {
+			return errors.New("missing arg index " + strconv.Itoa(index))
+		}
+		if err := opts.addPositional(def); err != nil {
+			return err
+		}
+	}
+	if rest != nil {
+		if err := opts.addPositional(*rest); err != nil {
+			return err
+		}
+	}
+	return groups.register(opts)
+}
+
+func (opts *GetOpt) bindPositional(target reflect.Value, fieldType reflect.StructField, fieldValue reflect.Value, variadic bool) (posDef, error) {
+	var def posDef
+	if !fieldType.IsExported() {
+		return def, errors.New("can't use args for unexported fieldType " + fieldType.Name)
+	}
+	name := fieldType.Tag.Get("name")
+	if name == "" {
+		name = strings.ToUpper(fieldType.Name)
+	}
+	help := fieldType.Tag.Get("help")
+	if variadic {
+		min, max := 0, 0
+		var err error
+		if found, ok := fieldType.Tag.Lookup("min"); ok {
+			if min, err = strconv.Atoi(found); err != nil {
+				return def, err
+			}
+		}
+		if found, ok := fieldType.Tag.Lookup("max"); ok {
+			if max, err = strconv.Atoi(found); err != nil {
+				return def, err
+			}
+		}
+		def = positionalList(name, min, max, help)
+	} else {
+		optional, _ := strconv.ParseBool(fieldType.Tag.Get("optional"))
+		_, hasDefault := fieldType.Tag.Lookup("default")
+		def = positional(name, !optional && !hasDefault, help)
+	}
+	if variadic {
+		def.argReset = clearField(fieldValue)
+	}
+	if def.argConv = opts.fieldCallback(fieldType, fieldValue); def.argConv == nil {
+		return def, errors.New("unsupported type " + fieldType.Type.Kind().String() + " for " + fieldType.Name)
+	}
+	if found, ok := fieldType.Tag.Lookup("complete"); ok {
+		var err error
+		if def.completer, err = methodCompleter(target, found); err != nil {
+			return def, err
+		}
+	}
+	if found, ok := fieldType.Tag.Lookup("env"); ok {
+		def.env = append(def.env, found)
+	}
+	if val, ok := fieldType.Tag.Lookup("default"); ok {
+		return def, def.argConv(val)
+	}
+	return def, nil
+}
+
+func methodCompleter(target reflect.Value, name string) (func(string) []string, error) {
+	method := target.MethodByName(name)
+	if !method.IsValid() {
+		return nil, errors.New("completer method " + name + " not found on " + target.Type().String())
+	}
+	completer, ok := method.Interface().(func(string) []string)
+	if !ok {
+		return nil, errors.New("completer method " + name + " has to be func(string) []string")
+	}
+	return completer, nil
+}
+
+func (opts *GetOpt) fieldCallback(fieldType reflect.StructField, fieldValue reflect.Value) func(string) error {
+	var callback func(string) error
+	switch value := fieldValue.Interface().(type) {
+	case string:
+		callback = func(strval string) error {
+			fieldValue.Set(reflect.ValueOf(strval))
+			return nil
+		}
+	case []string:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().([]string)
+			value = append(value, strval)
+			fieldValue.Set(reflect.ValueOf(value))
+			return nil
+		}
+	case map[string]string:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().(map[string]string)
+			key, val := getKeyValue(strval)
+			if value == nil {
+				value = make(map[string]string)
+			}
+			value[key] = val
+			fieldValue.Set(reflect.ValueOf(value))
+			return nil
+		}
+	case uint64:
+		callback = func(strval string) error {
+			value, err := strconv.ParseUint(strval, 0, 64)
+			if err == nil {
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case []uint64:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().([]uint64)
+			val, err := strconv.ParseUint(strval, 0, 64)
+			if err == nil {
+				value = append(value, val)
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case map[string]uint64:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().(map[string]uint64)
+			key, sval := getKeyValue(strval)
+			if val, err := strconv.ParseUint(sval, 0, 64); err != nil {
+				return err
+			} else {
+				if value == nil {
+					value = make(map[string]uint64)
 				}
-			case map[string]time.Time:
-				callback = func(strval string) error {
-					key, sval := getKeyValue(strval)
-					if val, err := time.Parse(time.RFC3339, sval); err != nil {
-						return err
-					} else {
-						if value == nil {
-							value = make(map[string]time.Time)
-						}
-						value[key] = val
-						fieldValue.Set(reflect.ValueOf(value))
-						return nil
-					}
+				value[key] = val
+				fieldValue.Set(reflect.ValueOf(value))
+				return nil
+			}
+		}
+	case uint:
+		callback = func(strval string) error {
+			value, err := strconv.ParseUint(strval, 0, 32)
+			if err == nil {
+				fieldValue.Set(reflect.ValueOf(uint(value)))
+			}
+			return err
+		}
+	case []uint:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().([]uint)
+			val, err := strconv.ParseUint(strval, 0, 32)
+			if err == nil {
+				value = append(value, uint(val))
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case map[string]uint:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().(map[string]uint)
+			key, sval := getKeyValue(strval)
+			if val, err := strconv.ParseUint(sval, 0, 32); err != nil {
+				return err
+			} else {
+				if value == nil {
+					value = make(map[string]uint)
 				}
-			case time.Duration:
-				callback = func(strval string) error {
-					value, err := time.ParseDuration(strval)
-					if err == nil {
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
+				value[key] = uint(val)
+				fieldValue.Set(reflect.ValueOf(value))
+				return nil
+			}
+		}
+	case int64:
+		callback = func(strval string) error {
+			value, err := strconv.ParseInt(strval, 0, 64)
+			if err == nil {
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case []int64:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().([]int64)
+			val, err := strconv.ParseInt(strval, 0, 64)
+			if err == nil {
+				value = append(value, val)
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case map[string]int64:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().(map[string]int64)
+			key, sval := getKeyValue(strval)
+			if val, err := strconv.ParseInt(sval, 0, 64); err != nil {
+				return err
+			} else {
+				if value == nil {
+					value = make(map[string]int64)
 				}
-			case []time.Duration:
-				callback = func(strval string) error {
-					val, err := time.ParseDuration(strval)
-					if err == nil {
-						value = append(value, val)
-						fieldValue.Set(reflect.ValueOf(value))
-					}
-					return err
+				value[key] = val
+				fieldValue.Set(reflect.ValueOf(value))
+				return nil
+			}
+		}
+	case int:
+		callback = func(strval string) error {
+			value, err := strconv.ParseInt(strval, 0, 32)
+			if err == nil {
+				fieldValue.Set(reflect.ValueOf(int(value)))
+			}
+			return err
+		}
+	case []int:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().([]int)
+			val, err := strconv.ParseInt(strval, 0, 32)
+			if err == nil {
+				value = append(value, int(val))
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case map[string]int:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().(map[string]int)
+			key, sval := getKeyValue(strval)
+			if val, err := strconv.ParseInt(sval, 0, 32); err != nil {
+				return err
+			} else {
+				if value == nil {
+					value = make(map[string]int)
 				}
-			case map[string]time.Duration:
-				callback = func(strval string) error {
-					key, sval := getKeyValue(strval)
-					if val, err := time.ParseDuration(sval); err != nil {
-						return err
-					} else {
-						if value == nil {
-							value = make(map[string]time.Duration)
-						}
-						value[key] = val
-						fieldValue.Set(reflect.ValueOf(value))
-						return nil
-					}
+				value[key] = int(val)
+				fieldValue.Set(reflect.ValueOf(value))
+				return nil
+			}
+		}
+	case float64:
+		callback = func(strval string) error {
+			value, err := strconv.ParseFloat(strval, 64)
+			if err == nil {
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case []float64:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().([]float64)
+			val, err := strconv.ParseFloat(strval, 64)
+			if err == nil {
+				value = append(value, val)
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case map[string]float64:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().(map[string]float64)
+			key, sval := getKeyValue(strval)
+			if val, err := strconv.ParseFloat(sval, 64); err != nil {
+				return err
+			} else {
+				if value == nil {
+					value = make(map[string]float64)
 				}
-			default:
-				return nil, errors.New("unsupported type " + fieldType.Type.Kind().String() + " for " + fieldType.Name)
+				value[key] = val
+				fieldValue.Set(reflect.ValueOf(value))
+				return nil
 			}
-			if callback != nil {
-				err = opts.ArgFuncV(flags, longopts, callback, help)
-				if err == nil {
-					var val *string
-					if found, ok := fieldType.Tag.Lookup("default"); ok {
-						val = &found
-					}
-					if found, ok := fieldType.Tag.Lookup("env"); ok {
-						if found, ok := os.LookupEnv(found); ok {
-							val = &found
-						}
-					}
-					if val != nil {
-						err = callback(*val)
-					}
+		}
+	case float32:
+		callback = func(strval string) error {
+			value, err := strconv.ParseFloat(strval, 32)
+			if err == nil {
+				fieldValue.Set(reflect.ValueOf(float32(value)))
+			}
+			return err
+		}
+	case []float32:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().([]float32)
+			val, err := strconv.ParseFloat(strval, 32)
+			if err == nil {
+				value = append(value, float32(val))
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case map[string]float32:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().(map[string]float32)
+			key, sval := getKeyValue(strval)
+			if val, err := strconv.ParseFloat(sval, 32); err != nil {
+				return err
+			} else {
+				if value == nil {
+					value = make(map[string]float32)
 				}
-			} else if trigger != nil {
-				err = opts.FlagFuncV(flags, longopts, trigger, help)
-				if err == nil {
-					var val bool
-					if found, ok := fieldType.Tag.Lookup("default"); ok {
-						val, err = strconv.ParseBool(found)
-					}
-					if err == nil {
-						if found, ok := fieldType.Tag.Lookup("env"); ok {
-							if found, ok := os.LookupEnv(found); ok {
-								val, err = strconv.ParseBool(found)
-							}
-						}
-					}
-					if err == nil && val {
-						trigger()
-					}
+				value[key] = float32(val)
+				fieldValue.Set(reflect.ValueOf(value))
+				return nil
+			}
+		}
+	case time.Time:
+		callback = func(strval string) error {
+			value, err := time.Parse(time.RFC3339, strval)
+			if err == nil {
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case []time.Time:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().([]time.Time)
+			val, err := time.Parse(time.RFC3339, strval)
+			if err == nil {
+				value = append(value, val)
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case map[string]time.Time:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().(map[string]time.Time)
+			key, sval := getKeyValue(strval)
+			if val, err := time.Parse(time.RFC3339, sval); err != nil {
+				return err
+			} else {
+				if value == nil {
+					value = make(map[string]time.Time)
 				}
+				value[key] = val
+				fieldValue.Set(reflect.ValueOf(value))
+				return nil
 			}
-			if err != nil {
-				opts.done = true
-				return nil, err
+		}
+	case time.Duration:
+		callback = func(strval string) error {
+			value, err := time.ParseDuration(strval)
+			if err == nil {
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case []time.Duration:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().([]time.Duration)
+			val, err := time.ParseDuration(strval)
+			if err == nil {
+				value = append(value, val)
+				fieldValue.Set(reflect.ValueOf(value))
+			}
+			return err
+		}
+	case map[string]time.Duration:
+		callback = func(strval string) error {
+			value = fieldValue.Interface().(map[string]time.Duration)
+			key, sval := getKeyValue(strval)
+			if val, err := time.ParseDuration(sval); err != nil {
+				return err
+			} else {
+				if value == nil {
+					value = make(map[string]time.Duration)
+				}
+				value[key] = val
+				fieldValue.Set(reflect.ValueOf(value))
+				return nil
 			}
 		}
+	default:
+		callback = opts.fileCallback(fieldType, fieldValue)
 	}
-	return opts.Parse(argv, posix)
+	return callback
+}
+
+func clearField(fieldValue reflect.Value) func() {
+	return func() {
+		fieldValue.Set(reflect.Zero(fieldValue.Type()))
+	}
+}
+
+func fieldArgType(fieldType reflect.Type) string {
+	prefix := ""
+	if fieldType.Kind() == reflect.Map {
+		prefix = "key:"
+	}
+	if fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Map {
+		fieldType = fieldType.Elem()
+	}
+	switch fieldType {
+	case reflect.TypeOf(time.Duration(0)):
+		return prefix + "duration"
+	case reflect.TypeOf(time.Time{}):
+		return prefix + "time"
+	case readerType, readCloserType, inputFileType, writerType, writeCloserType, outputFileType, osFileType:
+		return "file"
+	}
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int64:
+		return prefix + "int"
+	case reflect.Uint, reflect.Uint64:
+		return prefix + "uint"
+	case reflect.Float32, reflect.Float64:
+		return prefix + "float"
+	case reflect.String:
+		return prefix + "string"
+	}
+	return "value"
+}
+
+func boolDefault(tag reflect.StructTag) (bool, error) {
+	var val bool
+	var err error
+	if found, ok := tag.Lookup("default"); ok {
+		val, err = strconv.ParseBool(found)
+	}
+	return val, err
+}
+
+func (opts *GetOpt) bindCommand(name string, fieldType reflect.StructField, fieldValue reflect.Value) error {
+	var cmdValue reflect.Value
+	if fieldType.Type.Kind() == reflect.Ptr && fieldType.Type.Elem().Kind() == reflect.Struct {
+		if fieldValue.IsNil() {
+			cmdValue = reflect.New(fieldType.Type.Elem())
+		} else {
+			cmdValue = fieldValue
+		}
+	} else if fieldType.Type.Kind() == reflect.Struct {
+		cmdValue = fieldValue.Addr()
+	} else {
+		return errors.New("struct or struct pointer expected for command " + fieldType.Name)
+	}
+	cmd := opts.Command(name, fieldType.Tag.Get("help"), func(cmd *GetOpt, args []string) error {
+		if runner, ok := cmdValue.Interface().(interface{ Run() error }); ok {
+			return runner.Run()
+		}
+		return nil
+	})
+	cmd.onSelect = func() {
+		if fieldType.Type.Kind() == reflect.Ptr {
+			fieldValue.Set(cmdValue)
+		}
+	}
+	return cmd.bind(cmdValue.Interface())
 }
 
 func getKeyValue(arg string) (key, value string) {
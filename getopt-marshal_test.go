@@ -24,6 +24,8 @@ type testMarshal struct {
 	WaitList  []time.Duration    `flag:"D,duration-list" help:"duration list"`
 	Exec      func(string) error `flag:"x,exec" help:"execute cmd"`
 	Show      func() error       `flag:"show" help:"show stats"`
+	Color     string             `flag:"c,color" implicit:"auto" help:"colorize output"`
+	Level     int                `flag:"O,level" implicit:"2" default:"0" help:"optimization level"`
 }
 
 func TestGetOpt_Marshal(t *testing.T) {
@@ -61,6 +63,28 @@ func TestGetOpt_Marshal(t *testing.T) {
 			"",
 			false,
 		},
+		{
+			"optional arguments",
+			New().WithDefaults("prog", "v0"),
+			args{
+				&testMarshal{},
+				[]string{
+					"prog",
+					"--color",
+					"-O",
+					"une",
+				},
+				true,
+			},
+			testMarshal{
+				Color: "auto",
+				Level: 2,
+			},
+			[]string{"une"},
+			false,
+			"",
+			false,
+		},
 		{
 			"happy path",
 			New().WithDefaults("prog", "v0"),
@@ -146,3 +170,44 @@ func TestGetOpt_Marshal(t *testing.T) {
 		})
 	}
 }
+
+type testDeployCmd struct {
+	Env string `flag:"e,env" default:"dev" help:"environment"`
+	ran bool
+}
+
+func (cmd *testDeployCmd) Run() error {
+	cmd.ran = true
+	return nil
+}
+
+type testStatusCmd struct {
+	Short bool `flag:"s,short" help:"short output"`
+}
+
+type testToolCmd struct {
+	Verbose bool           `flag:"v,verbose" help:"verbose output"`
+	Deploy  *testDeployCmd `cmd:"deploy" help:"deploy application"`
+	Status  testStatusCmd  `cmd:"status" help:"show status"`
+}
+
+func TestGetOpt_MarshalCommands(t *testing.T) {
+	tool := testToolCmd{}
+	args, err := New().WithDefaults("tool", "v0").Marshal(&tool, []string{"tool", "deploy", "--env=prod", "-v", "app"}, false)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if tool.Deploy == nil || tool.Deploy.Env != "prod" || !tool.Deploy.ran || !tool.Verbose {
+		t.Errorf("Marshal() deploy = %+v, tool = %+v", tool.Deploy, tool)
+	}
+	if !reflect.DeepEqual(args, []string{"app"}) {
+		t.Errorf("Marshal() args = %v", args)
+	}
+	tool = testToolCmd{}
+	if _, err = New().Marshal(&tool, []string{"tool", "status", "-s"}, false); err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if tool.Deploy != nil || !tool.Status.Short || tool.Verbose {
+		t.Errorf("Marshal() status = %+v", tool)
+	}
+}
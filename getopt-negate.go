@@ -0,0 +1,52 @@
+package getopt
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+func (opts *GetOpt) Negatable(option string) error {
+	item, found := opts.optionMap[option]
+	if !found {
+		return errors.New("Unknown option `" + option + "`")
+	}
+	if item.argNegate == nil {
+		return errors.New("Option " + option + " can not be negated")
+	}
+	for _, longOpt := range item.longOpts {
+		if !strings.HasPrefix(longOpt, "--") {
+			continue
+		}
+		negated := "--no-" + longOpt[2:]
+		if slices.Contains(item.negated, negated) {
+			continue
+		}
+		if err := opts.safeAddKey(negated, item); err != nil {
+			return err
+		}
+		item.negated = append(item.negated, negated)
+	}
+	if len(item.negated) == 0 {
+		return errors.New("Option " + option + " has no long form to negate")
+	}
+	return nil
+}
+
+func (opts *GetOpt) flagFunc(flags []rune, longFlags []string, action func(bool), help string) error {
+	def := optDef{
+		posixOpts: flags,
+		longOpts:  longFlags,
+		help:      help,
+		noArg:     true,
+	}
+	def.argConv = func(string) error {
+		action(true)
+		return nil
+	}
+	def.argNegate = func() error {
+		action(false)
+		return nil
+	}
+	return opts.safeAdd(def)
+}
@@ -0,0 +1,112 @@
+package getopt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGetOpt_Negatable(t *testing.T) {
+	type test struct {
+		name        string
+		args        []string
+		wantColor   bool
+		wantCache   bool
+		wantParseOk bool
+	}
+	tests := []test{
+		{"defaults", []string{"prog"}, false, true, true},
+		{"set", []string{"prog", "--color"}, true, true, true},
+		{"negated", []string{"prog", "--no-color", "--no-cache"}, false, false, true},
+		{"last wins", []string{"prog", "--color", "--no-color", "-c"}, true, true, true},
+		{"negated value option", []string{"prog", "--cache=false", "--no-cache"}, false, false, true},
+		{"negation takes no argument", []string{"prog", "--no-color=1"}, false, true, false},
+		{"abbreviated", []string{"prog", "--no-col"}, false, true, true},
+		{"not negatable", []string{"prog", "--no-verbose"}, false, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := New().WithAbbreviations(true).WithErrorHandler(func(err error, option Option) (bool, error) {
+				return true, err
+			})
+			color, _ := opts.Flag('c', "--color", "colorize")
+			cache, _ := opts.BoolDefault('C', "--cache", true, "use cache")
+			_, _ = opts.Flag('v', "--verbose", "verbose")
+			for _, option := range []string{"--color", "--cache"} {
+				if err := opts.Negatable(option); err != nil {
+					t.Fatalf("Unexpected error %v on setup", err)
+				}
+			}
+			if _, err := opts.Parse(test.args, false); (err == nil) != test.wantParseOk {
+				t.Errorf("Parse() error = %v, want ok = %v", err, test.wantParseOk)
+			} else if err == nil && (*color != test.wantColor || *cache != test.wantCache) {
+				t.Errorf("Unexpected values: %v, %v (expected: %v, %v)", *color, *cache, test.wantColor, test.wantCache)
+			}
+		})
+	}
+}
+
+func TestGetOpt_NegatableSetup(t *testing.T) {
+	opts := New()
+	_, _ = opts.StringValue('s', "--str", false, "string")
+	_, _ = opts.Flag('x', "", "short only")
+	_, _ = opts.Flag('n', "--no-color", "explicit negation")
+	_, _ = opts.Flag('c', "--color", "colorize")
+	for _, option := range []string{"--unknown", "--str", "-x", "--color"} {
+		if err := opts.Negatable(option); err == nil {
+			t.Errorf("Negatable(%v) expected to fail", option)
+		}
+	}
+}
+
+func TestGetOpt_NegatableHelp(t *testing.T) {
+	opts := New()
+	_, _ = opts.Flag('c', "--color", "colorize")
+	_ = opts.Negatable("--color")
+	if help := opts.HelpString(); !strings.Contains(help, "--[no-]color") {
+		t.Errorf("HelpString() = %q, want --[no-]color", help)
+	}
+}
+
+func TestGetOpt_NegatableArgHelp(t *testing.T) {
+	opts := New()
+	_, _ = opts.BoolDefault('b', "--bool", true, "enable")
+	_ = opts.Negatable("--bool")
+	var man, md bytes.Buffer
+	_ = opts.WriteManPage(&man)
+	_ = opts.WriteMarkdown(&md)
+	tests := []struct {
+		name, out, want, bad string
+	}{
+		{"help", opts.HelpString(), "--bool=bool, --no-bool", "--[no-]bool"},
+		{"man", man.String(), `\fB\-\-bool\fR=\fIbool\fR, \fB\-\-no\-bool\fR`, `[no\-]`},
+		{"markdown", md.String(), "--no-bool", "--[no-]bool"},
+	}
+	for _, test := range tests {
+		if !strings.Contains(test.out, test.want) || strings.Contains(test.out, test.bad) {
+			t.Errorf("%s output = %q, want %q", test.name, test.out, test.want)
+		}
+	}
+}
+
+func TestGetOpt_MarshalNegatable(t *testing.T) {
+	type target struct {
+		Color bool `flag:"color" negatable:"true" default:"true"`
+	}
+	tests := []struct {
+		args      []string
+		wantValue bool
+	}{
+		{[]string{"prog"}, true},
+		{[]string{"prog", "--no-color"}, false},
+		{[]string{"prog", "--no-color", "--color"}, true},
+	}
+	for _, test := range tests {
+		result := target{}
+		if _, err := New().Marshal(&result, test.args, false); err != nil {
+			t.Errorf("Marshal(%v) unexpected error %v", test.args, err)
+		} else if result.Color != test.wantValue {
+			t.Errorf("Marshal(%v) = %v, want %v", test.args, result.Color, test.wantValue)
+		}
+	}
+}
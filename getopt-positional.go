@@ -0,0 +1,137 @@
+package getopt
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+type posDef struct {
+	name      string
+	help      string
+	min       int
+	max       int
+	variadic  bool
+	count     int
+	argConv   func(string) error
+	argReset  func()
+	env       []string
+	completer func(string) []string
+}
+
+func (posDef *posDef) Reset() {
+	if posDef.argReset != nil {
+		posDef.argReset()
+	}
+	posDef.count = 0
+}
+
+func (posDef *posDef) String() string {
+	name := posDef.name
+	if posDef.variadic {
+		name += "..."
+	}
+	if posDef.min == 0 {
+		name = "[" + name + "]"
+	}
+	return name
+}
+
+func (opts *GetOpt) PositionalFunc(name string, required bool, action func(string) error, help string) error {
+	def := positional(name, required, help)
+	def.argConv = action
+	return opts.addPositional(def)
+}
+
+func (opts *GetOpt) PositionalListFunc(name string, min int, max int, action func(string) error, help string) error {
+	def := positionalList(name, min, max, help)
+	def.argConv = action
+	return opts.addPositional(def)
+}
+
+func positional(name string, required bool, help string) posDef {
+	def := posDef{
+		name: name,
+		help: help,
+		max:  1,
+	}
+	if required {
+		def.min = 1
+	}
+	return def
+}
+
+func positionalList(name string, min int, max int, help string) posDef {
+	return posDef{
+		name:     name,
+		help:     help,
+		min:      min,
+		max:      max,
+		variadic: true,
+	}
+}
+
+func (opts *GetOpt) addPositional(def posDef) error {
+	if def.max < 0 || (def.max > 0 && def.max < def.min) {
+		return errors.New("Invalid arity " + strconv.Itoa(def.min) + ".." + strconv.Itoa(def.max) + " for " + def.name)
+	}
+	for _, pos := range opts.positionals {
+		if pos.name == def.name {
+			return errors.New("Duplicate positional argument: " + def.name)
+		} else if pos.variadic {
+			return errors.New("Positional argument " + def.name + " follows variadic " + pos.name)
+		} else if pos.min == 0 && def.min > 0 {
+			return errors.New("Required positional argument " + def.name + " follows optional " + pos.name)
+		}
+	}
+	opts.positionals = append(opts.positionals, &def)
+	return nil
+}
+
+func (opts *GetOpt) applyPositional(position int, arg string) error {
+	if len(opts.positionals) == 0 {
+		return nil
+	}
+	for _, pos := range opts.positionals {
+		if position < pos.max || (pos.variadic && pos.max == 0) {
+			err := pos.argConv(arg)
+			if err == nil {
+				pos.count++
+			}
+			return err
+		}
+		position -= pos.max
+	}
+	return errors.New("Unexpected argument `" + arg + "`")
+}
+
+func (opts *GetOpt) checkPositional(pos *posDef) error {
+	if pos.count >= pos.min {
+		return nil
+	} else if pos.variadic && pos.min > 1 {
+		return errors.New("At least " + strconv.Itoa(pos.min) + " arguments required for " + pos.name)
+	}
+	return errors.New("Missing argument " + pos.name)
+}
+
+func (opts *GetOpt) Synopsis() string {
+	synopsis := []string{"Usage:"}
+	if name := opts.fullName(); name != "" {
+		synopsis = append(synopsis, name)
+	}
+	return strings.Join(append(synopsis, opts.synopsisArgs()...), " ")
+}
+
+func (opts *GetOpt) synopsisArgs() []string {
+	synopsis := make([]string, 0)
+	if len(opts.optionList) > 0 {
+		synopsis = append(synopsis, "[options]")
+	}
+	if len(opts.commands) > 0 {
+		synopsis = append(synopsis, "command")
+	}
+	for _, pos := range opts.positionals {
+		synopsis = append(synopsis, pos.String())
+	}
+	return synopsis
+}
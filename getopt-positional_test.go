@@ -0,0 +1,196 @@
+package getopt
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetOpt_Positional(t *testing.T) {
+	type test struct {
+		name        string
+		args        []string
+		wantSrc     string
+		wantCount   int64
+		wantFiles   []string
+		wantParseOk bool
+	}
+	tests := []test{
+		{"required only", []string{"prog", "a"}, "a", 1, []string{}, true},
+		{"all", []string{"prog", "a", "3", "x", "y"}, "a", 3, []string{"x", "y"}, true},
+		{"mixed with options", []string{"prog", "a", "-v", "3", "x"}, "a", 3, []string{"x"}, true},
+		{"missing required", []string{"prog"}, "", 1, []string{}, false},
+		{"invalid conversion", []string{"prog", "a", "three"}, "", 1, []string{}, false},
+		{"too many", []string{"prog", "a", "3", "x", "y", "z"}, "", 1, []string{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+				return true, err
+			})
+			_, _ = opts.Flag('v', "--verbose", "verbose")
+			src, err := opts.StringPositional("SRC", true, "source")
+			if err != nil {
+				t.Fatalf("Unexpected error %v on setup", err)
+			}
+			count, _ := opts.IntPositionalDefault("COUNT", 1, "count")
+			files, err := opts.StringPositionalList("FILE", 0, 2, "files")
+			if err != nil {
+				t.Fatalf("Unexpected error %v on setup", err)
+			}
+			if _, err := opts.Parse(test.args, false); (err == nil) != test.wantParseOk {
+				t.Errorf("Parse() error = %v, want ok = %v", err, test.wantParseOk)
+			} else if err == nil && (*src != test.wantSrc || *count != test.wantCount || !reflect.DeepEqual(*files, test.wantFiles)) {
+				t.Errorf("Unexpected values: %v, %v, %v", *src, *count, *files)
+			}
+		})
+	}
+}
+
+func TestGetOpt_PositionalDefault(t *testing.T) {
+	opts := New()
+	name, _ := opts.StringPositionalDefault("NAME", "world", "name")
+	count, _ := opts.UintPositionalDefault("COUNT", 2, "count")
+	ratio, _ := opts.FloatPositionalDefault("RATIO", 0.5, "ratio")
+	if _, err := opts.Parse([]string{"prog", "you", "3", "1.5"}, false); err != nil {
+		t.Fatalf("Unexpected error %v on parse", err)
+	}
+	if *name != "you" || *count != 3 || *ratio != 1.5 {
+		t.Errorf("Unexpected values: %v, %v, %v", *name, *count, *ratio)
+	}
+	opts.ResetValues()
+	if *name != "world" || *count != 2 || *ratio != 0.5 {
+		t.Errorf("ResetValues() = %v, %v, %v", *name, *count, *ratio)
+	}
+	if _, err := opts.Parse([]string{"prog"}, false); err != nil || *name != "world" {
+		t.Errorf("Parse() = %v, error = %v", *name, err)
+	}
+	if synopsis := opts.Synopsis(); synopsis != "Usage: [NAME] [COUNT] [RATIO]" {
+		t.Errorf("Synopsis() = %q", synopsis)
+	}
+}
+
+func TestGetOpt_PositionalHelp(t *testing.T) {
+	tests := [][]string{
+		{"prog", "-h"},
+		{"prog", "--completion=bash"},
+		{"prog", "--generate-man"},
+		{"prog", "deploy", "-h"},
+	}
+	for _, args := range tests {
+		opts := New().WithDefaults("prog", "1.0").WithOutput(io.Discard, io.Discard)
+		opts.AddCompletion()
+		opts.AddManPage()
+		if args[1] == "deploy" {
+			deploy := opts.Command("deploy", "Deploy application", nil)
+			_, _ = deploy.StringPositional("APP", true, "application")
+		} else {
+			_, _ = opts.StringPositional("SRC", true, "source")
+		}
+		if _, err := opts.Parse(args, false); err != nil || !opts.Done() {
+			t.Errorf("Parse(%q) error = %v, done = %v", args, err, opts.Done())
+		}
+	}
+}
+
+func TestGetOpt_PositionalSetup(t *testing.T) {
+	opts := New()
+	_, _ = opts.StringPositional("SRC", false, "source")
+	if _, err := opts.StringPositional("DST", true, "destination"); err == nil {
+		t.Errorf("required after optional expected to fail")
+	}
+	if _, err := opts.StringPositional("SRC", false, "source"); err == nil {
+		t.Errorf("duplicate expected to fail")
+	}
+	if _, err := opts.StringPositionalList("FILE", 2, 1, "files"); err == nil {
+		t.Errorf("invalid arity expected to fail")
+	}
+	_, _ = opts.StringPositionalList("FILE", 0, 0, "files")
+	if _, err := opts.StringPositional("LAST", false, "last"); err == nil {
+		t.Errorf("positional after variadic expected to fail")
+	}
+}
+
+func TestGetOpt_PositionalList(t *testing.T) {
+	opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+		return true, err
+	})
+	values, _ := opts.FloatPositionalList("VALUE", 2, 0, "values")
+	if _, err := opts.Parse([]string{"prog", "1.5"}, false); err == nil {
+		t.Errorf("Parse() expected to fail with too few arguments")
+	}
+	opts.ResetValues()
+	if _, err := opts.Parse([]string{"prog", "1.5", "2", "3"}, false); err != nil {
+		t.Errorf("Parse() unexpected error %v", err)
+	} else if !reflect.DeepEqual(*values, []float64{1.5, 2, 3}) {
+		t.Errorf("Unexpected value: %v", *values)
+	}
+}
+
+func TestGetOpt_Synopsis(t *testing.T) {
+	opts := New().WithDefaults("cp", "1.0")
+	_, _ = opts.StringPositional("SRC", true, "source")
+	_, _ = opts.StringPositional("DST", false, "destination")
+	_, _ = opts.StringPositionalList("FILE", 0, 0, "files")
+	if got, want := opts.Synopsis(), "Usage: cp [options] SRC [DST] [FILE...]"; got != want {
+		t.Errorf("Synopsis() = %q, want %q", got, want)
+	}
+}
+
+func TestGetOpt_MarshalPositional(t *testing.T) {
+	type target struct {
+		Verbose bool          `flag:"v,verbose"`
+		Dst     string        `arg:"1" name:"DST"`
+		Src     string        `arg:"0" name:"SRC" help:"source"`
+		Files   []int         `args:"rest" min:"1" max:"2"`
+		Wait    time.Duration `flag:"w,wait"`
+	}
+	tests := []struct {
+		args        []string
+		want        target
+		wantParseOk bool
+	}{
+		{[]string{"prog", "a", "b", "1"}, target{Src: "a", Dst: "b", Files: []int{1}}, true},
+		{[]string{"prog", "-v", "a", "b", "1", "2"}, target{Verbose: true, Src: "a", Dst: "b", Files: []int{1, 2}}, true},
+		{[]string{"prog", "a", "b"}, target{}, false},
+		{[]string{"prog", "a"}, target{}, false},
+		{[]string{"prog", "a", "b", "1", "2", "3"}, target{}, false},
+		{[]string{"prog", "a", "b", "x"}, target{}, false},
+	}
+	for _, test := range tests {
+		result := target{}
+		opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+			return true, err
+		})
+		if _, err := opts.Marshal(&result, test.args, false); (err == nil) != test.wantParseOk {
+			t.Errorf("Marshal(%v) error = %v, want ok = %v", test.args, err, test.wantParseOk)
+		} else if err == nil && !reflect.DeepEqual(result, test.want) {
+			t.Errorf("Marshal(%v) = %v, want %v", test.args, result, test.want)
+		}
+	}
+	opts := New()
+	_ = opts.bind(&target{})
+	if got, want := opts.Synopsis(), "Usage: [options] SRC DST FILES..."; got != want {
+		t.Errorf("Synopsis() = %q, want %q", got, want)
+	}
+}
+
+func TestGetOpt_MarshalPositionalSetup(t *testing.T) {
+	type gap struct {
+		First string `arg:"0"`
+		Third string `arg:"2"`
+	}
+	type duplicate struct {
+		First  string `arg:"0"`
+		Second string `arg:"0"`
+	}
+	type unsupported struct {
+		First chan int `arg:"0"`
+	}
+	for _, target := range []interface{}{&gap{}, &duplicate{}, &unsupported{}} {
+		if err := New().bind(target); err == nil {
+			t.Errorf("bind(%T) expected to fail", target)
+		}
+	}
+}
@@ -15,7 +15,6 @@ func (opts *GetOpt) StringValueV(flags []rune, longFlags []string, required bool
 	}
 	def.argConv = func(arg string) error {
 		result = arg
-		def.count++
 		return nil
 	}
 	def.argReset = func() {
@@ -34,11 +33,36 @@ func (opts *GetOpt) StringDefaultV(flags []rune, longFlags []string, value strin
 		posixOpts: flags,
 		longOpts:  longFlags,
 		help:      help,
+		defValue:  value,
 		argType:   "string",
 	}
 	def.argConv = func(arg string) error {
 		result = arg
-		def.count++
+		return nil
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.safeAdd(def)
+}
+
+func (opts *GetOpt) StringOptional(flag rune, longFlag string, value string, implicit string, help string) (*string, error) {
+	return opts.StringOptionalV([]rune{flag}, []string{longFlag}, value, implicit, help)
+}
+
+func (opts *GetOpt) StringOptionalV(flags []rune, longFlags []string, value string, implicit string, help string) (*string, error) {
+	result := value
+	def := optDef{
+		posixOpts:   flags,
+		longOpts:    longFlags,
+		help:        help,
+		defValue:    value,
+		optionalArg: true,
+		implicit:    implicit,
+		argType:     "string",
+	}
+	def.argConv = func(arg string) error {
+		result = arg
 		return nil
 	}
 	def.argReset = func() {
@@ -57,11 +81,12 @@ func (opts *GetOpt) StringListV(flags []rune, longFlags []string, help string) (
 		posixOpts: flags,
 		longOpts:  longFlags,
 		help:      help,
+		multiple:  true,
+		list:      true,
 		argType:   "string",
 	}
 	def.argConv = func(arg string) error {
 		result = append(result, arg)
-		def.count++
 		return nil
 	}
 	def.argReset = func() {
@@ -69,3 +94,37 @@ func (opts *GetOpt) StringListV(flags []rune, longFlags []string, help string) (
 	}
 	return &result, opts.safeAdd(def)
 }
+
+func (opts *GetOpt) StringPositional(name string, required bool, help string) (*string, error) {
+	return opts.stringPositional(name, required, "", help)
+}
+
+func (opts *GetOpt) StringPositionalDefault(name string, value string, help string) (*string, error) {
+	return opts.stringPositional(name, false, value, help)
+}
+
+func (opts *GetOpt) stringPositional(name string, required bool, value string, help string) (*string, error) {
+	result := value
+	def := positional(name, required, help)
+	def.argConv = func(arg string) error {
+		result = arg
+		return nil
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.addPositional(def)
+}
+
+func (opts *GetOpt) StringPositionalList(name string, min int, max int, help string) (*[]string, error) {
+	result := make([]string, 0)
+	def := positionalList(name, min, max, help)
+	def.argConv = func(arg string) error {
+		result = append(result, arg)
+		return nil
+	}
+	def.argReset = func() {
+		result = make([]string, 0)
+	}
+	return &result, opts.addPositional(def)
+}
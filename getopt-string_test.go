@@ -50,15 +50,15 @@ func TestGetOpt_String(t *testing.T) {
 			wantParseOk: false,
 		},
 		{
-			name: "optional longopt separated arg should fail",
+			name: "optional longopt separated arg",
 			init: func(getopt *GetOpt) (*string, error) {
 				return getopt.StringValue('f', "--str", false, "help")
 			},
 			args:        []string{"prog", "--str", "val"},
 			posix:       true,
 			wantSetupOk: true,
-			wantValue:   "",
-			wantParseOk: false,
+			wantValue:   "val",
+			wantParseOk: true,
 		},
 		{
 			name: "optional opt set",
@@ -93,6 +93,17 @@ func TestGetOpt_String(t *testing.T) {
 			wantValue:   "",
 			wantParseOk: false,
 		},
+		{
+			name: "required flag set",
+			init: func(getopt *GetOpt) (*string, error) {
+				return getopt.StringValue('f', "--str", true, "help")
+			},
+			args:        []string{"prog", "--str=abc"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   "abc",
+			wantParseOk: true,
+		},
 		{
 			name: "required flag fails if missing",
 			init: func(getopt *GetOpt) (*string, error) {
@@ -126,6 +137,61 @@ func TestGetOpt_String(t *testing.T) {
 			wantValue:   "world",
 			wantParseOk: true,
 		},
+		{
+			name: "optional argument absent",
+			init: func(getopt *GetOpt) (*string, error) {
+				return getopt.StringOptional('f', "--str", "dflt", "impl", "help")
+			},
+			args:        []string{"prog"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   "dflt",
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument implicit",
+			init: func(getopt *GetOpt) (*string, error) {
+				return getopt.StringOptional('f', "--str", "dflt", "impl", "help")
+			},
+			args:        []string{"prog", "-f"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   "impl",
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument inline",
+			init: func(getopt *GetOpt) (*string, error) {
+				return getopt.StringOptional('f', "--str", "dflt", "impl", "help")
+			},
+			args:        []string{"prog", "-fabc"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   "abc",
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument longopt inline",
+			init: func(getopt *GetOpt) (*string, error) {
+				return getopt.StringOptional('f', "--str", "dflt", "impl", "help")
+			},
+			args:        []string{"prog", "--str=abc"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   "abc",
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument not taken from next word",
+			init: func(getopt *GetOpt) (*string, error) {
+				return getopt.StringOptional('f', "--str", "dflt", "impl", "help")
+			},
+			args:        []string{"prog", "--str", "abc"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   "impl",
+			wantParseOk: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
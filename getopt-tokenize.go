@@ -2,12 +2,19 @@ package getopt
 
 import (
 	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"sort"
 	"strings"
 )
 
 type Option struct {
-	Opt string
-	Arg *string
+	Opt    string
+	Arg    *string
+	Index  int
+	Offset int
+	Raw    string
 }
 
 func (option Option) String() string {
@@ -21,94 +28,288 @@ func (option Option) String() string {
 		} else {
 			return option.Opt
 		}
-	} else {
+	} else if option.Arg != nil {
 		return "'" + *option.Arg + "'"
+	} else {
+		return ""
+	}
+}
+
+const (
+	NoArgument = iota
+	RequiredArgument
+	OptionalArgument
+)
+
+type LongOption struct {
+	Name   string
+	HasArg int
+}
+
+type TokenizeMode int
+
+const (
+	AbbreviateLong TokenizeMode = 1 << iota
+	GreedyOptional
+)
+
+var (
+	ErrUnknownOption      = errors.New("unknown option")
+	ErrAmbiguousOption    = errors.New("ambiguous option")
+	ErrMissingArgument    = errors.New("missing argument")
+	ErrUnexpectedArgument = errors.New("unexpected argument")
+)
+
+type OptionError struct {
+	Code       rune
+	Option     string
+	Index      int
+	Offset     int
+	Candidates []string
+	Err        error
+}
+
+func (e *OptionError) Error() string {
+	switch e.Err {
+	case ErrUnknownOption:
+		return "Unknown option " + e.Option
+	case ErrAmbiguousOption:
+		return "Ambiguous option " + e.Option + ", could be " + strings.Join(e.Candidates, "/")
+	case ErrMissingArgument:
+		return "Missing argument to required option " + e.Option
+	case ErrUnexpectedArgument:
+		return "Option " + e.Option + " does not take an argument"
+	default:
+		return e.Err.Error() + " " + e.Option
+	}
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+func OptionErrors(err error) []*OptionError {
+	result := make([]*OptionError, 0)
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			result = append(result, OptionErrors(e)...)
+		}
+	} else if optErr := (*OptionError)(nil); errors.As(err, &optErr) {
+		result = append(result, optErr)
 	}
+	return result
 }
 
 type getoptConfig struct {
 	dontPrintErrors  bool
 	positionalAsArgs bool
 	posixlyCorrect   bool
+	abbreviate       bool
+	greedyOptional   bool
 	optmap           map[rune]int
+	longopts         map[string]int
 }
 
 func Tokenize(args []string, options string) ([]Option, error) {
-	if cfg, err := newGetoptConfig(options); err != nil {
-		return nil, err
-	} else {
-		result := make([]Option, 0)
-		tail := make([]Option, 0)
-		nextOpt := rune(0)
-		nextOptIsOpt := false
-		for pos, arg := range args[1:] {
-			if nextOpt != 0 && nextOptIsOpt == false {
-				sarg := arg
-				result = append(result, Option{"-" + string(nextOpt), &sarg})
-				nextOpt = 0
-			} else {
-				l := len(arg)
-				if l > 0 && arg[0] == '-' { // -
-					if nextOpt != 0 {
-						result = append(result, Option{"-" + string(nextOpt), nil})
-						nextOpt = 0
-						nextOptIsOpt = false
+	return TokenizeLong(args, options, nil)
+}
+
+func TokenizeLong(args []string, options string, longopts []LongOption, modes ...TokenizeMode) ([]Option, error) {
+	result := make([]Option, 0)
+	errs := make([]error, 0)
+	for opt, err := range TokenizeLongSeq(args, options, longopts, modes...) {
+		if err == nil {
+			result = append(result, opt)
+		} else if _, ok := err.(*OptionError); ok {
+			errs = append(errs, err)
+		} else {
+			return nil, err
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+func TokenizeSeq(args []string, options string) iter.Seq2[Option, error] {
+	return TokenizeLongSeq(args, options, nil)
+}
+
+func TokenizeLongSeq(args []string, options string, longopts []LongOption, modes ...TokenizeMode) iter.Seq2[Option, error] {
+	return func(yield func(Option, error) bool) {
+		if cfg, err := newGetoptConfig(options); err != nil {
+			yield(Option{}, err)
+		} else {
+			for _, mode := range modes {
+				cfg.abbreviate = cfg.abbreviate || mode&AbbreviateLong != 0
+				cfg.greedyOptional = cfg.greedyOptional || mode&GreedyOptional != 0
+			}
+			if longopts != nil {
+				cfg.longopts = make(map[string]int)
+				for _, longopt := range longopts {
+					cfg.longopts[longopt.Name] = longopt.HasArg
+				}
+			}
+			cfg.scan(args, yield)
+		}
+	}
+}
+
+func (cfg getoptConfig) scan(args []string, yield func(Option, error) bool) {
+	emit := func(opt string, arg *string, index int, offset int) bool {
+		return yield(Option{Opt: opt, Arg: arg, Index: index, Offset: offset, Raw: args[index]}, nil)
+	}
+	fail := func(err *OptionError) bool {
+		if err.Code == 0 {
+			err.Code = '?'
+			if err.Err == ErrMissingArgument && cfg.dontPrintErrors {
+				err.Code = ':'
+			}
+		}
+		if !cfg.dontPrintErrors {
+			_, _ = fmt.Fprintln(os.Stderr, args[0]+":", err)
+		}
+		return yield(Option{Opt: err.Option, Index: err.Index, Offset: err.Offset, Raw: args[err.Index]}, err)
+	}
+	tail := make([]int, 0)
+	nextOpt := ""
+	nextOptIsOpt := false
+	nextIndex, nextOffset := 0, 0
+	for pos := 1; pos < len(args); pos++ {
+		arg := args[pos]
+		if nextOpt != "" && (!nextOptIsOpt || len(arg) < 2 || arg[0] != '-') {
+			sarg := arg
+			if !emit(nextOpt, &sarg, nextIndex, nextOffset) {
+				return
+			}
+			nextOpt = ""
+			nextOptIsOpt = false
+			continue
+		}
+		l := len(arg)
+		if l > 1 && arg[0] == '-' { // -
+			if nextOpt != "" {
+				if !emit(nextOpt, nil, nextIndex, nextOffset) {
+					return
+				}
+				nextOpt = ""
+				nextOptIsOpt = false
+			}
+			if arg[1] == '-' { // --
+				if l > 2 { // --flag
+					name := arg
+					var sarg *string
+					if eq := strings.Index(arg, "="); eq >= 0 {
+						name = arg[0:eq]
+						value := string(arg[eq+1:])
+						sarg = &value
 					}
-					if l > 1 && arg[1] == '-' { // --
-						if l > 2 { // --flag
-							if eq := strings.Index(arg, "="); eq < 0 {
-								result = append(result, Option{arg, nil})
-							} else {
-								sarg := string(arg[eq+1:])
-								result = append(result, Option{arg[0:eq], &sarg})
-							}
-						} else { // --
-							for p := pos + 2; p < len(args); p++ {
-								sarg := args[p]
-								result = append(result, Option{"", &sarg})
-							}
-							break
-						}
-					} else { // -a
-						argrunes := []rune(arg)[1:]
-						for chpos, ch := range argrunes {
-							if optType := cfg.optmap[ch]; optType == 0 {
-								result = append(result, Option{"-" + string(ch), nil})
-							} else {
-								if chpos+1 == len(argrunes) {
-									nextOpt = ch
-									if optType > 1 {
-										nextOptIsOpt = true
-									}
-								} else {
-									sarg := string(argrunes[chpos+1:])
-									result = append(result, Option{"-" + string(ch), &sarg})
-									break
-								}
-							}
-						}
+					ok := true
+					if cfg.longopts == nil {
+						ok = emit(name, sarg, pos, 0)
+					} else if resolved, optType, resolveErr := cfg.resolveLong(name); resolveErr != nil {
+						resolveErr.Index = pos
+						ok = fail(resolveErr)
+					} else if optType == NoArgument && sarg != nil {
+						ok = fail(&OptionError{Option: resolved, Index: pos, Err: ErrUnexpectedArgument})
+					} else if optType == RequiredArgument && sarg == nil {
+						nextOpt, nextIndex, nextOffset = resolved, pos, 0
+					} else if optType == OptionalArgument && sarg == nil && cfg.greedyOptional {
+						nextOpt, nextIndex, nextOffset = resolved, pos, 0
+						nextOptIsOpt = true
+					} else {
+						ok = emit(resolved, sarg, pos, 0)
 					}
-				} else if cfg.posixlyCorrect {
+					if !ok {
+						return
+					}
+				} else { // --
 					for p := pos + 1; p < len(args); p++ {
 						sarg := args[p]
-						result = append(result, Option{"", &sarg})
+						if !emit("", &sarg, p, 0) {
+							return
+						}
 					}
 					break
-				} else {
-					sarg := arg
-					tail = append(tail, Option{"", &sarg})
 				}
+			} else { // -a
+				argrunes := []rune(arg)[1:]
+				for chpos, ch := range argrunes {
+					ok := true
+					if optType, found := cfg.optmap[ch]; !found {
+						ok = fail(&OptionError{Option: "-" + string(ch), Index: pos, Offset: chpos + 1, Err: ErrUnknownOption})
+					} else if optType == NoArgument {
+						ok = emit("-"+string(ch), nil, pos, chpos+1)
+					} else if chpos+1 < len(argrunes) {
+						sarg := string(argrunes[chpos+1:])
+						if !emit("-"+string(ch), &sarg, pos, chpos+1) {
+							return
+						}
+						break
+					} else if optType == RequiredArgument {
+						nextOpt, nextIndex, nextOffset = "-"+string(ch), pos, chpos+1
+					} else if cfg.greedyOptional {
+						nextOpt, nextIndex, nextOffset = "-"+string(ch), pos, chpos+1
+						nextOptIsOpt = true
+					} else {
+						ok = emit("-"+string(ch), nil, pos, chpos+1)
+					}
+					if !ok {
+						return
+					}
+				}
+			}
+		} else if cfg.positionalAsArgs {
+			sarg := arg
+			if !emit("", &sarg, pos, 0) {
+				return
+			}
+		} else if cfg.posixlyCorrect {
+			for p := pos; p < len(args); p++ {
+				sarg := args[p]
+				if !emit("", &sarg, p, 0) {
+					return
+				}
+			}
+			break
+		} else {
+			tail = append(tail, pos)
+		}
+	}
+	if nextOpt != "" {
+		if nextOptIsOpt {
+			if !emit(nextOpt, nil, nextIndex, nextOffset) {
+				return
 			}
+		} else if !fail(&OptionError{Option: nextOpt, Index: nextIndex, Offset: nextOffset, Err: ErrMissingArgument}) {
+			return
 		}
-		var err error
-		if nextOpt != 0 {
-			result = append(result, Option{"-" + string(nextOpt), nil})
-			if !nextOptIsOpt {
-				err = errors.New("Missing argument to required option -" + string(nextOpt))
+	}
+	for _, p := range tail {
+		sarg := args[p]
+		if !emit("", &sarg, p, 0) {
+			return
+		}
+	}
+}
+
+func (cfg getoptConfig) resolveLong(name string) (string, int, *OptionError) {
+	if optType, found := cfg.longopts[name[2:]]; found {
+		return name, optType, nil
+	}
+	if cfg.abbreviate {
+		candidates := make([]string, 0)
+		for longopt := range cfg.longopts {
+			if strings.HasPrefix(longopt, name[2:]) {
+				candidates = append(candidates, "--"+longopt)
 			}
 		}
-		return append(result, tail...), err
+		if len(candidates) == 1 {
+			return candidates[0], cfg.longopts[candidates[0][2:]], nil
+		} else if len(candidates) > 1 {
+			sort.Strings(candidates)
+			return name, 0, &OptionError{Option: name, Candidates: candidates, Err: ErrAmbiguousOption}
+		}
 	}
+	return name, 0, &OptionError{Option: name, Err: ErrUnknownOption}
 }
 
 func newGetoptConfig(options string) (getoptConfig, error) {
@@ -1,6 +1,9 @@
 package getopt
 
 import (
+	"errors"
+	"io"
+	"os"
 	"reflect"
 	"testing"
 )
@@ -9,10 +12,20 @@ func mkstr(str string) *string {
 	return &str
 }
 
+func withoutPositions(opts []Option) []Option {
+	result := make([]Option, 0, len(opts))
+	for _, opt := range opts {
+		result = append(result, Option{Opt: opt.Opt, Arg: opt.Arg})
+	}
+	return result
+}
+
 func TestTokenize(t *testing.T) {
 	type args struct {
-		args    []string
-		options string
+		args     []string
+		options  string
+		longopts []LongOption
+		modes    []TokenizeMode
 	}
 	tests := []struct {
 		name    string
@@ -109,21 +122,333 @@ func TestTokenize(t *testing.T) {
 				{Arg: mkstr("theta")},
 			},
 		},
+		{
+			name: "returnInOrder",
+			args: args{
+				args: []string{
+					"cmd",
+					"-hV",
+					"alpha",
+					"-o",
+					"output",
+					"beta",
+					"-l",
+					"gamma",
+					"--",
+					"-o",
+					"theta",
+				},
+				options: "-hVlo:",
+			},
+			want: []Option{
+				{Opt: "-h"},
+				{Opt: "-V"},
+				{Arg: mkstr("alpha")},
+				{Opt: "-o", Arg: mkstr("output")},
+				{Arg: mkstr("beta")},
+				{Opt: "-l"},
+				{Arg: mkstr("gamma")},
+				{Arg: mkstr("-o")},
+				{Arg: mkstr("theta")},
+			},
+		},
+		{
+			name: "optionalArguments",
+			args: args{
+				args: []string{
+					"cmd",
+					"-t",
+					"zeta",
+					"-t5",
+					"--tab",
+					"kappa",
+					"--tab=4",
+					"-xt",
+					"-x",
+				},
+				options:  "t::x",
+				longopts: []LongOption{{"tab", OptionalArgument}},
+			},
+			want: []Option{
+				{Opt: "-t"},
+				{Opt: "-t", Arg: mkstr("5")},
+				{Opt: "--tab"},
+				{Opt: "--tab", Arg: mkstr("4")},
+				{Opt: "-x"},
+				{Opt: "-t"},
+				{Opt: "-x"},
+				{Arg: mkstr("zeta")},
+				{Arg: mkstr("kappa")},
+			},
+		},
+		{
+			name: "greedyOptionalArguments",
+			args: args{
+				args: []string{
+					"cmd",
+					"-t",
+					"zeta",
+					"-t5",
+					"--tab",
+					"kappa",
+					"--tab=4",
+					"-xt",
+					"-x",
+					"--tab",
+				},
+				options:  "t::x",
+				longopts: []LongOption{{"tab", OptionalArgument}},
+				modes:    []TokenizeMode{GreedyOptional},
+			},
+			want: []Option{
+				{Opt: "-t", Arg: mkstr("zeta")},
+				{Opt: "-t", Arg: mkstr("5")},
+				{Opt: "--tab", Arg: mkstr("kappa")},
+				{Opt: "--tab", Arg: mkstr("4")},
+				{Opt: "-x"},
+				{Opt: "-t"},
+				{Opt: "-x"},
+				{Opt: "--tab"},
+			},
+		},
+		{
+			name: "loneDash",
+			args: args{
+				args:    []string{"cmd", "-", "-o", "-", "-t", "-", "-x"},
+				options: "o:t::x",
+				modes:   []TokenizeMode{GreedyOptional},
+			},
+			want: []Option{
+				{Opt: "-o", Arg: mkstr("-")},
+				{Opt: "-t", Arg: mkstr("-")},
+				{Opt: "-x"},
+				{Arg: mkstr("-")},
+			},
+		},
+		{
+			name: "longOptions",
+			args: args{
+				args: []string{
+					"cmd",
+					"--input", "in",
+					"--output=out",
+					"--verbose",
+					"--tab",
+					"zeta",
+					"--tab=4",
+				},
+				options: "v",
+				longopts: []LongOption{
+					{"input", RequiredArgument},
+					{"output", RequiredArgument},
+					{"verbose", NoArgument},
+					{"tab", OptionalArgument},
+				},
+			},
+			want: []Option{
+				{Opt: "--input", Arg: mkstr("in")},
+				{Opt: "--output", Arg: mkstr("out")},
+				{Opt: "--verbose"},
+				{Opt: "--tab"},
+				{Opt: "--tab", Arg: mkstr("4")},
+				{Arg: mkstr("zeta")},
+			},
+		},
+		{
+			name: "unknownLongOption",
+			args: args{
+				args:     []string{"cmd", "--verbose", "--quiet", "zeta"},
+				longopts: []LongOption{{"verbose", NoArgument}},
+			},
+			want: []Option{
+				{Opt: "--verbose"},
+				{Arg: mkstr("zeta")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missingLongArgument",
+			args: args{
+				args:     []string{"cmd", "--input"},
+				longopts: []LongOption{{"input", RequiredArgument}},
+			},
+			want:    []Option{},
+			wantErr: true,
+		},
+		{
+			name: "unexpectedLongArgument",
+			args: args{
+				args:     []string{"cmd", "--verbose=yes"},
+				longopts: []LongOption{{"verbose", NoArgument}},
+			},
+			want:    []Option{},
+			wantErr: true,
+		},
+		{
+			name: "abbreviatedLongOptions",
+			args: args{
+				args:     []string{"cmd", "--verb", "--in", "file", "--ver"},
+				longopts: []LongOption{{"verbose", NoArgument}, {"version", NoArgument}, {"ver", NoArgument}, {"input", RequiredArgument}},
+				modes:    []TokenizeMode{AbbreviateLong},
+			},
+			want: []Option{
+				{Opt: "--verbose"},
+				{Opt: "--input", Arg: mkstr("file")},
+				{Opt: "--ver"},
+			},
+		},
+		{
+			name: "ambiguousLongOption",
+			args: args{
+				args:     []string{"cmd", "--ver"},
+				longopts: []LongOption{{"verbose", NoArgument}, {"version", NoArgument}},
+				modes:    []TokenizeMode{AbbreviateLong},
+			},
+			want:    []Option{},
+			wantErr: true,
+		},
+		{
+			name: "abbreviationsDisabled",
+			args: args{
+				args:     []string{"cmd", "--verb"},
+				longopts: []LongOption{{"verbose", NoArgument}},
+			},
+			want:    []Option{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := Tokenize(tt.args.args, tt.args.options)
+			got, err := TokenizeLong(tt.args.args, tt.args.options, tt.args.longopts, tt.args.modes...)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Tokenize() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
+			if !reflect.DeepEqual(withoutPositions(got), tt.want) {
+				t.Errorf("Tokenize() got/want =\n %v\n %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		options   string
+		want      []Option
+		wantCodes []rune
+		wantOpts  []string
+		wantErrs  []error
+	}{
+		{
+			name:      "unknownOptionsKeepTokenizing",
+			args:      []string{"cmd", "-xqy", "-w", "zeta", "-o"},
+			options:   ":xyo:",
+			want:      []Option{{Opt: "-x"}, {Opt: "-y"}, {Arg: mkstr("zeta")}},
+			wantCodes: []rune{'?', '?', ':'},
+			wantOpts:  []string{"-q", "-w", "-o"},
+			wantErrs:  []error{ErrUnknownOption, ErrUnknownOption, ErrMissingArgument},
+		},
+		{
+			name:      "missingArgumentWithoutColon",
+			args:      []string{"cmd", "-x", "-o"},
+			options:   "xo:",
+			want:      []Option{{Opt: "-x"}},
+			wantCodes: []rune{'?'},
+			wantOpts:  []string{"-o"},
+			wantErrs:  []error{ErrMissingArgument},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Tokenize(tt.args, tt.options)
+			if !reflect.DeepEqual(withoutPositions(got), tt.want) {
 				t.Errorf("Tokenize() got/want =\n %v\n %v", got, tt.want)
 			}
+			optErrs := OptionErrors(err)
+			if len(optErrs) != len(tt.wantCodes) {
+				t.Fatalf("Tokenize() errors = %v, want %d errors", err, len(tt.wantCodes))
+			}
+			for i, optErr := range optErrs {
+				if optErr.Code != tt.wantCodes[i] || optErr.Option != tt.wantOpts[i] || !errors.Is(optErr, tt.wantErrs[i]) {
+					t.Errorf("Tokenize() error %d = %c %v %v, want %c %v %v", i,
+						optErr.Code, optErr.Option, optErr.Err, tt.wantCodes[i], tt.wantOpts[i], tt.wantErrs[i])
+				}
+			}
 		})
 	}
 }
 
+func TestTokenizeSeq(t *testing.T) {
+	args := []string{"cmd", "-a", "-bvalue", "sub", "-c", "--", "-d"}
+	want, wantErr := Tokenize(args, "ab:c")
+	got := make([]Option, 0)
+	for opt, err := range TokenizeSeq(args, "ab:c") {
+		if err != nil {
+			t.Fatalf("TokenizeSeq() error = %v", err)
+		}
+		got = append(got, opt)
+	}
+	if wantErr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("TokenizeSeq() got/want =\n %v\n %v", got, want)
+	}
+	got = make([]Option, 0)
+	for opt, err := range TokenizeSeq(args, "+:ab:") {
+		if err != nil {
+			t.Fatalf("TokenizeSeq() error = %v", err)
+		}
+		got = append(got, opt)
+		if opt.Opt == "" {
+			break
+		}
+	}
+	want = []Option{{Opt: "-a"}, {Opt: "-b", Arg: mkstr("value")}, {Arg: mkstr("sub")}}
+	if !reflect.DeepEqual(withoutPositions(got), want) {
+		t.Errorf("TokenizeSeq() early stop got/want =\n %v\n %v", got, want)
+	}
+}
+
+func TestTokenizeSeqErrors(t *testing.T) {
+	got := make([]Option, 0)
+	errs := make([]error, 0)
+	for opt, err := range TokenizeSeq([]string{"cmd", "-axb"}, ":ab") {
+		got = append(got, opt)
+		errs = append(errs, err)
+	}
+	want := []Option{{Opt: "-a"}, {Opt: "-x"}, {Opt: "-b"}}
+	if !reflect.DeepEqual(withoutPositions(got), want) {
+		t.Errorf("TokenizeSeq() got/want =\n %v\n %v", got, want)
+	}
+	if errs[0] != nil || !errors.Is(errs[1], ErrUnknownOption) || errs[2] != nil {
+		t.Errorf("TokenizeSeq() errors = %v", errs)
+	}
+}
+
+func TestTokenizePositions(t *testing.T) {
+	args := []string{"cmd", "zeta", "-xo", "out", "--tab=4", "-qt5", "--", "-y"}
+	got, err := TokenizeLong(args, ":xo:qt::", []LongOption{{"tab", OptionalArgument}})
+	if err != nil {
+		t.Fatalf("Tokenize() error = %v", err)
+	}
+	want := []Option{
+		{Opt: "-x", Index: 2, Offset: 1, Raw: "-xo"},
+		{Opt: "-o", Arg: mkstr("out"), Index: 2, Offset: 2, Raw: "-xo"},
+		{Opt: "--tab", Arg: mkstr("4"), Index: 4, Raw: "--tab=4"},
+		{Opt: "-q", Index: 5, Offset: 1, Raw: "-qt5"},
+		{Opt: "-t", Arg: mkstr("5"), Index: 5, Offset: 2, Raw: "-qt5"},
+		{Arg: mkstr("-y"), Index: 7, Raw: "-y"},
+		{Arg: mkstr("zeta"), Index: 1, Raw: "zeta"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() got/want =\n %#v\n %#v", got, want)
+	}
+	_, err = Tokenize([]string{"cmd", "-xzq"}, ":xq")
+	if optErrs := OptionErrors(err); len(optErrs) != 1 || optErrs[0].Index != 1 || optErrs[0].Offset != 2 {
+		t.Errorf("Tokenize() error position = %v", optErrs)
+	}
+}
+
 func makeOptMap(values ...rune) map[rune]int {
 	result := make(map[rune]int)
 	for i, val := range values {
@@ -202,3 +527,37 @@ func Test_newGetoptConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestOption_String(t *testing.T) {
+	tests := []struct {
+		option Option
+		want   string
+	}{
+		{Option{Opt: "-v"}, "-v"},
+		{Option{Opt: "-o", Arg: mkstr("file")}, "-o 'file'"},
+		{Option{Opt: "--output", Arg: mkstr("file")}, "--output='file'"},
+		{Option{Arg: mkstr("operand")}, "'operand'"},
+		{Option{}, ""},
+	}
+	for _, test := range tests {
+		if got := test.option.String(); got != test.want {
+			t.Errorf("String() = %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestTokenizeSilent(t *testing.T) {
+	read, write, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = write
+	_, silentErr := Tokenize([]string{"cmd", "-x", "-b"}, "+:ab:")
+	os.Stderr = stderr
+	_ = write.Close()
+	output, _ := io.ReadAll(read)
+	if len(OptionErrors(silentErr)) != 2 || len(output) > 0 {
+		t.Errorf("Tokenize() error = %v, stderr = %q", silentErr, output)
+	}
+}
@@ -3,20 +3,42 @@ package getopt
 import (
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
 )
 
 type optDef struct {
-	posixOpts []rune
-	longOpts  []string
-	help      string
-	noArg     bool
-	required  bool
-	multiple  bool
-	count     int
-	argConv   func(string) error
-	argReset  func()
-	argType   string
+	posixOpts   []rune
+	longOpts    []string
+	help        string
+	noArg       bool
+	optionalArg bool
+	implicit    string
+	defValue    string
+	required    bool
+	hidden      bool
+	multiple    bool
+	list        bool
+	count       int
+	argConv     func(string) error
+	argReset    func()
+	argNegate   func() error
+	negated     []string
+	choices     []string
+	env         []string
+	completer   func(string) []string
+	argType     string
+}
+
+func (optDef *optDef) key() string {
+	if len(optDef.longOpts) > 0 {
+		return optDef.longOpts[0]
+	}
+	return "-" + string(optDef.posixOpts[0])
 }
 
 func (optDef *optDef) Reset() {
@@ -30,26 +52,64 @@ type ErrorHandler interface {
 	Handle(err error, option Option) error
 }
 
+type PositionError struct {
+	Err    error
+	Args   []string
+	Option Option
+}
+
+func (e *PositionError) Error() string {
+	column := e.Option.Offset
+	for _, arg := range e.Args[:e.Option.Index] {
+		column += utf8.RuneCountInString(arg) + 1
+	}
+	return strings.Join(e.Args, " ") + "\n" + strings.Repeat(" ", column) + "^ " + e.Err.Error()
+}
+
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
+
 type GetOpt struct {
+	abbreviate   bool
+	closers      []io.Closer
+	command      *GetOpt
+	commandHelp  string
+	commands     []*GetOpt
+	completion   bool
 	description  []string
 	done         bool
 	errorHandler func(err error, option Option) (bool, error)
+	groups       []optGroup
+	handler      func(cmd *GetOpt, args []string) error
+	helpWidth    int
 	name         string
-	optionMap    map[string]optDef
-	optionList   []optDef
+	onSelect     func()
+	opens        []func() error
+	optionMap    map[string]*optDef
+	optionList   []*optDef
+	parent       *GetOpt
+	positionals  []*posDef
+	stderr       io.Writer
+	stdout       io.Writer
 	version      string
 }
 
 func New() *GetOpt {
-	return &GetOpt{
-		errorHandler: func(err error, option Option) (bool, error) {
-			_, _ = fmt.Fprintln(os.Stderr, err, " while handling ", option)
-			return true, err
-		},
+	opts := &GetOpt{
 		description: make([]string, 0),
-		optionMap:   make(map[string]optDef),
-		optionList:  make([]optDef, 0),
+		optionMap:   make(map[string]*optDef),
+		optionList:  make([]*optDef, 0),
 	}
+	opts.errorHandler = func(err error, option Option) (bool, error) {
+		if _, ok := err.(*PositionError); ok {
+			_, _ = fmt.Fprintln(opts.errorOutput(), err)
+		} else {
+			_, _ = fmt.Fprintln(opts.errorOutput(), err, " while handling ", option)
+		}
+		return true, err
+	}
+	return opts
 }
 
 func (opts *GetOpt) AddDefaults(name string, version string, description []string) {
@@ -74,8 +134,48 @@ func (opts *GetOpt) WithErrorHandler(handler func(err error, option Option) (boo
 	return opts
 }
 
+func (opts *GetOpt) SetAbbreviations(enable bool) {
+	opts.abbreviate = enable
+}
+
+func (opts *GetOpt) WithAbbreviations(enable bool) *GetOpt {
+	opts.SetAbbreviations(enable)
+	return opts
+}
+
+func (opts *GetOpt) SetOutput(stdout io.Writer, stderr io.Writer) {
+	opts.stdout = stdout
+	opts.stderr = stderr
+}
+
+func (opts *GetOpt) WithOutput(stdout io.Writer, stderr io.Writer) *GetOpt {
+	opts.SetOutput(stdout, stderr)
+	return opts
+}
+
+func (opts *GetOpt) output() io.Writer {
+	for cmd := opts; cmd != nil; cmd = cmd.parent {
+		if cmd.stdout != nil {
+			return cmd.stdout
+		}
+	}
+	return os.Stdout
+}
+
+func (opts *GetOpt) errorOutput() io.Writer {
+	for cmd := opts; cmd != nil; cmd = cmd.parent {
+		if cmd.stderr != nil {
+			return cmd.stderr
+		}
+	}
+	return os.Stderr
+}
+
 func (opts *GetOpt) ResetValues() {
-	for _, v := range opts.optionMap {
+	for _, v := range opts.optionList {
+		v.Reset()
+	}
+	for _, v := range opts.positionals {
 		v.Reset()
 	}
 }
@@ -98,79 +198,238 @@ func (opts *GetOpt) separateFlagsFromLognopts(synonyms []string) ([]rune, []stri
 }
 
 func (opts *GetOpt) Parse(args []string, posix bool) ([]string, error) {
-	optstring := ""
-	if posix {
-		optstring += "+"
+	if opts.completion && len(args) > 1 && args[1] == completeCommand {
+		opts.done = true
+		return nil, opts.writeCandidates(opts.output(), args[2:])
 	}
-	for _, v := range opts.optionList {
-		for _, posixOpt := range v.posixOpts {
-			optstring += string(posixOpt)
-			if !v.noArg {
-				optstring += ":"
-			}
+	var err error
+	positional := make([]string, 0)
+	for arg, argErr := range opts.ParseSeq(args, posix) {
+		if argErr != nil {
+			err = argErr
+		} else {
+			positional = append(positional, arg)
 		}
 	}
-	content, err := Tokenize(args, optstring)
-	if err != nil {
-		opts.done, err = opts.errorHandler(err, Option{"", &args[0]})
+	if err == nil && !opts.Done() {
+		if openErr := opts.openFiles(); openErr != nil {
+			opts.done, err = opts.handleError(openErr, Option{})
+		}
 	}
-	positional := make([]string, 0)
-	for _, opt := range content {
-		if opt.Opt != "" {
-			if item, found := opts.optionMap[opt.Opt]; found == true {
-				if item.noArg {
-					err = item.argConv("")
-				} else if opt.Arg == nil {
-					err = errors.New("Argument required for " + opt.Opt + " (" + item.help + ")")
+	if err == nil && !opts.Done() {
+		err = opts.dispatch(positional)
+	}
+	return positional, err
+}
+
+func (opts *GetOpt) ParseSeq(args []string, posix bool) iter.Seq2[string, error] {
+	return opts.parseSeq(args, 0, posix)
+}
+
+func (opts *GetOpt) parseSeq(args []string, start int, posix bool) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		opts.command = nil
+		position := 0
+		optstring, longopts, mode := opts.tokenizerSpec(posix)
+		for opt, err := range TokenizeLongSeq(args[start:], optstring, longopts, mode) {
+			opt.Index += start
+			if optErr := (*OptionError)(nil); errors.As(err, &optErr) {
+				optErr.Index += start
+			}
+			if err == nil {
+				if opt.Opt == "" && opt.Arg != nil && len(opts.commands) > 0 {
+					for arg, err := range opts.commandSeq(args, opt, posix) {
+						if !yield(arg, err) {
+							return
+						}
+					}
+					break
+				} else if opt.Opt == "" && opt.Arg != nil {
+					err = opts.applyPositional(position, *opt.Arg)
+					position++
+					if err == nil {
+						if !yield(*opt.Arg, nil) {
+							return
+						}
+						continue
+					}
 				} else {
-					err = item.argConv(*opt.Arg)
+					err = opts.apply(opt)
+				}
+			}
+			if err != nil {
+				if opt.Index > 0 {
+					err = &PositionError{Err: err, Args: args, Option: opt}
+				}
+				if opts.done, err = opts.handleError(err, opt); err != nil && !yield("", err) {
+					return
 				}
-			} else {
-				err = errors.New("Unknown option `" + opt.Opt + "`")
 			}
-		} else if opt.Arg != nil {
-			positional = append(positional, *opt.Arg)
-		} else {
-			err = errors.New("Unexpedted empty option: no flag, no arg")
 		}
-		if err != nil {
-			opts.done, err = opts.errorHandler(err, opt)
+		for _, opt := range opts.optionList {
+			if err := opts.applyEnv(opt); err != nil {
+				if opts.done, err = opts.handleError(err, Option{Opt: opt.key()}); err != nil && !yield("", err) {
+					return
+				}
+			}
+		}
+		if len(opts.commands) > 0 && opts.command == nil && !opts.Done() {
+			var err error
+			if opts.done, err = opts.handleError(errors.New("Missing command"), Option{}); err != nil && !yield("", err) {
+				return
+			}
+		}
+		for _, pos := range opts.positionals {
+			if opts.Done() {
+				break
+			}
+			if err := opts.applyPositionalEnv(pos); err != nil {
+				if opts.done, err = opts.handleError(err, Option{}); err != nil && !yield("", err) {
+					return
+				}
+			} else if err := opts.checkPositional(pos); err != nil {
+				if opts.done, err = opts.handleError(err, Option{}); err != nil && !yield("", err) {
+					return
+				}
+			}
+		}
+		for _, opt := range opts.optionList {
+			if opt.required && opt.count == 0 && !opts.Done() {
+				var err error
+				if opts.done, err = opts.handleError(errors.New("Missing required option"), Option{Opt: opt.key()}); err != nil && !yield("", err) {
+					return
+				}
+			}
+		}
+		for _, group := range opts.groups {
+			if opts.Done() {
+				break
+			}
+			if err := opts.checkGroup(group); err != nil {
+				if opts.done, err = opts.handleError(err, Option{Opt: group.subject()}); err != nil && !yield("", err) {
+					return
+				}
+			}
 		}
 	}
-	for _, opt := range opts.optionList {
-		if opt.required && opt.count == 0 {
-			optflag := ""
-			if len(opt.longOpts) > 0 {
-				optflag = opt.longOpts[0]
-			} else {
-				optflag = "-" + string(opt.posixOpts[0])
+}
+
+func (opts *GetOpt) tokenizerSpec(posix bool) (string, []LongOption, TokenizeMode) {
+	optstring := ""
+	if posix || len(opts.commands) > 0 {
+		optstring += "+"
+	}
+	optstring += ":"
+	longopts := make([]LongOption, 0)
+	seen := make(map[string]bool)
+	var mode TokenizeMode
+	for cmd := opts; cmd != nil; cmd = cmd.parent {
+		if cmd.abbreviate {
+			mode |= AbbreviateLong
+		}
+		for _, v := range cmd.optionList {
+			hasArg := RequiredArgument
+			if v.noArg {
+				hasArg = NoArgument
+			} else if v.optionalArg {
+				hasArg = OptionalArgument
+			}
+			for _, posixOpt := range v.posixOpts {
+				if seen["-"+string(posixOpt)] {
+					continue
+				}
+				seen["-"+string(posixOpt)] = true
+				optstring += string(posixOpt)
+				if !v.noArg {
+					optstring += ":"
+				}
+				if v.optionalArg {
+					optstring += ":"
+				}
+			}
+			for _, longOpt := range v.longOpts {
+				if strings.HasPrefix(longOpt, "--") && !seen[longOpt] {
+					seen[longOpt] = true
+					longopts = append(longopts, LongOption{longOpt[2:], hasArg})
+				}
+			}
+			for _, negated := range v.negated {
+				if !seen[negated] {
+					seen[negated] = true
+					longopts = append(longopts, LongOption{negated[2:], NoArgument})
+				}
 			}
-			opts.done, err = opts.errorHandler(errors.New("Missing required option"), Option{optflag, nil})
 		}
 	}
-	return positional, err
+	return optstring, longopts, mode
+}
+
+func (opts *GetOpt) apply(opt Option) error {
+	if opt.Opt == "" {
+		return errors.New("Unexpedted empty option: no flag, no arg")
+	}
+	item, found := opts.lookup(opt.Opt)
+	var err error
+	if !found {
+		return errors.New("Unknown option `" + opt.Opt + "`")
+	} else if slices.Contains(item.negated, opt.Opt) && opt.Arg != nil {
+		return errors.New("Option " + opt.Opt + " does not take an argument")
+	} else if slices.Contains(item.negated, opt.Opt) {
+		err = item.argNegate()
+	} else if item.noArg {
+		err = item.argConv("")
+	} else if opt.Arg == nil && item.optionalArg {
+		err = item.argConv(item.implicit)
+	} else if opt.Arg == nil {
+		return errors.New("Argument required for " + opt.Opt + " (" + item.help + ")")
+	} else {
+		err = item.argConv(*opt.Arg)
+	}
+	if err == nil {
+		item.count++
+	}
+	return err
+}
+
+func (opts *GetOpt) lookup(option string) (*optDef, bool) {
+	for cmd := opts; cmd != nil; cmd = cmd.parent {
+		if item, found := cmd.optionMap[option]; found {
+			return item, true
+		}
+	}
+	return nil, false
+}
+
+func (opts *GetOpt) handleError(err error, option Option) (bool, error) {
+	for cmd := opts; cmd != nil; cmd = cmd.parent {
+		if cmd.errorHandler != nil {
+			return cmd.errorHandler(err, option)
+		}
+	}
+	return true, err
 }
 
 func (opts GetOpt) Done() bool {
-	return opts.done
+	return opts.done || (opts.command != nil && opts.command.Done())
 }
 
 func (opts *GetOpt) safeAdd(def optDef) error {
-	for _, posixOpt := range def.posixOpts {
-		if err := opts.safeAddKey(string("-"+string(posixOpt)), def); err != nil {
+	item := &def
+	for _, posixOpt := range item.posixOpts {
+		if err := opts.safeAddKey(string("-"+string(posixOpt)), item); err != nil {
 			return err
 		}
 	}
-	for _, longOpt := range def.longOpts {
-		if err := opts.safeAddKey(longOpt, def); err != nil {
+	for _, longOpt := range item.longOpts {
+		if err := opts.safeAddKey(longOpt, item); err != nil {
 			return err
 		}
 	}
-	opts.optionList = append(opts.optionList, def)
+	opts.optionList = append(opts.optionList, item)
 	return nil
 }
 
-func (opts *GetOpt) safeAddKey(option string, opt optDef) error {
+func (opts *GetOpt) safeAddKey(option string, opt *optDef) error {
 	if val, found := opts.optionMap[option]; found {
 		return errors.New("Duplicate optionMap key: " + option + ": " + val.help + " & " + opt.help)
 	} else {
@@ -179,46 +438,9 @@ func (opts *GetOpt) safeAddKey(option string, opt optDef) error {
 	return nil
 }
 
-func (opts *GetOpt) Help() error {
-	opts.done = true
-	// Posix requires help to be printed to stdout,
-	// that makes total sense as help is the requested result
-	for _, desc := range opts.description {
-		fmt.Println(desc)
-	}
-	for _, opt := range opts.optionList {
-		arg := opt.argType
-		nl := ""
-		for _, f := range opt.longOpts {
-			sep := "="
-			if arg == "" {
-				sep = ""
-			}
-			fmt.Printf("%s\t%s%s%s", nl, f, sep, arg)
-			nl = "\n"
-		}
-		for _, f := range opt.posixOpts {
-			fmt.Printf("%s\t-%c %s", nl, f, arg)
-			nl = "\n"
-		}
-		fmt.Print("\t")
-		nl = ""
-		if opt.required {
-			fmt.Print("required")
-			nl = ", "
-		}
-		if opt.multiple {
-			fmt.Printf("%smultiple", nl)
-			nl = ", "
-		}
-		fmt.Printf("%s%s\n", nl, opt.help)
-	}
-	return nil
-}
-
 func (opts *GetOpt) Version() error {
 	opts.done = true
-	fmt.Println(opts.name, opts.version)
+	fmt.Fprintln(opts.output(), opts.name, opts.version)
 	return nil
 }
 
@@ -0,0 +1,88 @@
+package getopt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetOpt_ParseSeq(t *testing.T) {
+	opts := New()
+	verbose, _ := opts.Flag('v', "--verbose", "verbose")
+	name, _ := opts.StringValue('n', "--name", false, "name")
+	got := make([]string, 0)
+	for arg, err := range opts.ParseSeq([]string{"prog", "-v", "sub", "--name=x", "tail"}, true) {
+		if err != nil {
+			t.Fatalf("ParseSeq() error = %v", err)
+		}
+		got = append(got, arg)
+		break
+	}
+	if !reflect.DeepEqual(got, []string{"sub"}) || !*verbose || *name != "" {
+		t.Errorf("ParseSeq() = %v, verbose = %v, name = %v", got, *verbose, *name)
+	}
+	got = make([]string, 0)
+	for arg, err := range opts.ParseSeq([]string{"prog", "one", "--name=x", "two"}, false) {
+		if err != nil {
+			t.Fatalf("ParseSeq() error = %v", err)
+		}
+		got = append(got, arg)
+	}
+	if !reflect.DeepEqual(got, []string{"one", "two"}) || *name != "x" {
+		t.Errorf("ParseSeq() = %v, name = %v", got, *name)
+	}
+}
+
+func TestGetOpt_Diagnostics(t *testing.T) {
+	var got []string
+	opts := New().WithErrorHandler(func(err error, option Option) (bool, error) {
+		got = append(got, err.Error())
+		return true, err
+	})
+	_, _ = opts.Flag('x', "--ex", "x")
+	_, _ = opts.Flag('q', "--qu", "q")
+	_, _ = opts.IntValue('n', "--num", false, "n")
+	_, err := opts.Parse([]string{"prog", "-xzq", "--num=abc"}, true)
+	if err == nil {
+		t.Fatalf("Parse() expected to fail")
+	}
+	want := []string{
+		"prog -xzq --num=abc\n       ^ Unknown option -z",
+		"prog -xzq --num=abc\n          ^ strconv.ParseInt: parsing \"abc\": invalid syntax",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() diagnostics got/want =\n%q\n%q", got, want)
+	}
+}
+
+func TestGetOpt_Output(t *testing.T) {
+	var stdout, stderr strings.Builder
+	opts := New().WithDefaults("prog", "1.0", "Usage: prog [opts]").WithOutput(&stdout, &stderr)
+	_, _ = opts.Flag('v', "--verbose", "verbose")
+	cmd := opts.Command("run", "Run it", nil)
+	if _, err := opts.Parse([]string{"prog", "--help"}, false); err != nil {
+		t.Fatalf("Parse() unexpected error %v", err)
+	}
+	if got := stdout.String(); got != opts.HelpString() || !strings.HasPrefix(got, "Usage: prog [opts]\n") {
+		t.Errorf("Help() = %q", got)
+	}
+	stdout.Reset()
+	if _, err := opts.Parse([]string{"prog", "-V"}, false); err != nil {
+		t.Fatalf("Parse() unexpected error %v", err)
+	}
+	if got := stdout.String(); got != "prog 1.0\n" {
+		t.Errorf("Version() = %q", got)
+	}
+	stdout.Reset()
+	_, _ = cmd.Flag('x', "--extra", "extra")
+	if _, err := opts.Parse([]string{"prog", "-v", "run", "-xz"}, false); err == nil {
+		t.Fatalf("Parse() expected to fail")
+	}
+	if got := stderr.String(); got != "prog -v run -xz\n"+"              ^ Unknown option -z\n" {
+		t.Errorf("error output = %q", got)
+	}
+	_ = cmd.Help()
+	if got := stdout.String(); !strings.HasPrefix(got, "prog run: Run it\n") {
+		t.Errorf("command Help() = %q", got)
+	}
+}
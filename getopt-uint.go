@@ -1,5 +1,7 @@
 package getopt
 
+import "strconv"
+
 func (opts *GetOpt) UintValue(flag rune, longFlag string, required bool, help string) (*uint64, error) {
 	return opts.UintValueV([]rune{flag}, []string{longFlag}, required, help)
 }
@@ -16,7 +18,6 @@ func (opts *GetOpt) UintValueV(flags []rune, longFlags []string, required bool,
 	def.argConv = func(arg string) error {
 		var err error
 		result, err = parseUint(arg)
-		def.count++
 		return err
 	}
 	def.argReset = func() {
@@ -35,12 +36,38 @@ func (opts *GetOpt) UintDefaultV(flags []rune, longFlags []string, value uint64,
 		posixOpts: flags,
 		longOpts:  longFlags,
 		help:      help,
+		defValue:  strconv.FormatUint(value, 10),
 		argType:   "uint",
 	}
 	def.argConv = func(arg string) error {
 		var err error
 		result, err = parseUint(arg)
-		def.count++
+		return err
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.safeAdd(def)
+}
+
+func (opts *GetOpt) UintOptional(flag rune, longFlag string, value uint64, implicit uint64, help string) (*uint64, error) {
+	return opts.UintOptionalV([]rune{flag}, []string{longFlag}, value, implicit, help)
+}
+
+func (opts *GetOpt) UintOptionalV(flags []rune, longFlags []string, value uint64, implicit uint64, help string) (*uint64, error) {
+	result := value
+	def := optDef{
+		posixOpts:   flags,
+		longOpts:    longFlags,
+		help:        help,
+		defValue:    strconv.FormatUint(value, 10),
+		optionalArg: true,
+		implicit:    strconv.FormatUint(implicit, 10),
+		argType:     "uint",
+	}
+	def.argConv = func(arg string) error {
+		var err error
+		result, err = parseUint(arg)
 		return err
 	}
 	def.argReset = func() {
@@ -59,12 +86,13 @@ func (opts *GetOpt) UintListV(flags []rune, longFlags []string, help string) (*[
 		posixOpts: flags,
 		longOpts:  longFlags,
 		help:      help,
+		multiple:  true,
+		list:      true,
 		argType:   "uint",
 	}
 	def.argConv = func(arg string) error {
 		if value, err := parseUint(arg); err == nil {
 			result = append(result, value)
-			def.count++
 			return nil
 		} else {
 			return err
@@ -75,3 +103,41 @@ func (opts *GetOpt) UintListV(flags []rune, longFlags []string, help string) (*[
 	}
 	return &result, opts.safeAdd(def)
 }
+
+func (opts *GetOpt) UintPositional(name string, required bool, help string) (*uint64, error) {
+	return opts.uintPositional(name, required, 0, help)
+}
+
+func (opts *GetOpt) UintPositionalDefault(name string, value uint64, help string) (*uint64, error) {
+	return opts.uintPositional(name, false, value, help)
+}
+
+func (opts *GetOpt) uintPositional(name string, required bool, value uint64, help string) (*uint64, error) {
+	result := value
+	def := positional(name, required, help)
+	def.argConv = func(arg string) error {
+		var err error
+		result, err = parseUint(arg)
+		return err
+	}
+	def.argReset = func() {
+		result = value
+	}
+	return &result, opts.addPositional(def)
+}
+
+func (opts *GetOpt) UintPositionalList(name string, min int, max int, help string) (*[]uint64, error) {
+	result := make([]uint64, 0)
+	def := positionalList(name, min, max, help)
+	def.argConv = func(arg string) error {
+		value, err := parseUint(arg)
+		if err == nil {
+			result = append(result, value)
+		}
+		return err
+	}
+	def.argReset = func() {
+		result = make([]uint64, 0)
+	}
+	return &result, opts.addPositional(def)
+}
@@ -115,6 +115,61 @@ func TestGetOpt_Uint(t *testing.T) {
 			wantValue:   4,
 			wantParseOk: true,
 		},
+		{
+			name: "optional argument absent",
+			init: func(getopt *GetOpt) (*uint64, error) {
+				return getopt.UintOptional('f', "--uint", 1, 2, "help")
+			},
+			args:        []string{"prog"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   1,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument implicit",
+			init: func(getopt *GetOpt) (*uint64, error) {
+				return getopt.UintOptional('f', "--uint", 1, 2, "help")
+			},
+			args:        []string{"prog", "-f"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   2,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument inline",
+			init: func(getopt *GetOpt) (*uint64, error) {
+				return getopt.UintOptional('f', "--uint", 1, 2, "help")
+			},
+			args:        []string{"prog", "-f10"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   10,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument longopt inline",
+			init: func(getopt *GetOpt) (*uint64, error) {
+				return getopt.UintOptional('f', "--uint", 1, 2, "help")
+			},
+			args:        []string{"prog", "--uint=10"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   10,
+			wantParseOk: true,
+		},
+		{
+			name: "optional argument not taken from next word",
+			init: func(getopt *GetOpt) (*uint64, error) {
+				return getopt.UintOptional('f', "--uint", 1, 2, "help")
+			},
+			args:        []string{"prog", "--uint", "10"},
+			posix:       true,
+			wantSetupOk: true,
+			wantValue:   2,
+			wantParseOk: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {